@@ -4,14 +4,49 @@ import "sync"
 
 //List of consts containing the names of the available components in the Application Context - appcontext.Current (Add your component names here as constants)
 const (
-ProjectDeleteUsecase = "ProjectDeleteUsecase"
-ProjectUpdateUsecase = "ProjectUpdateUsecase"
-ProjectGetByIDUsecase = "ProjectGetByIDUsecase"
-ProjectGetAllUsecase = "ProjectGetAllUsecase"
-ProjectCreateUsecase = "ProjectCreateUsecase"
-ProjectRepository = "ProjectRepository"
-DBClient = "DBClient"
-	Logger = "Logger"
+	ProjectDeleteUsecase     = "ProjectDeleteUsecase"
+	ProjectUpdateUsecase     = "ProjectUpdateUsecase"
+	ProjectGetByIDUsecase    = "ProjectGetByIDUsecase"
+	ProjectGetAllUsecase     = "ProjectGetAllUsecase"
+	ProjectCreateUsecase     = "ProjectCreateUsecase"
+	ProjectPriceUsecase      = "ProjectPriceUsecase"
+	ProjectQuoteUsecase      = "ProjectQuoteUsecase"
+	ProjectGetTrashUsecase   = "ProjectGetTrashUsecase"
+	ProjectRestoreUsecase    = "ProjectRestoreUsecase"
+	ProjectPurgeUsecase      = "ProjectPurgeUsecase"
+	ProjectHistoryUsecase    = "ProjectHistoryUsecase"
+	AuditSearchUsecase       = "AuditSearchUsecase"
+	ProjectGetVersionUsecase = "ProjectGetVersionUsecase"
+	ProjectGetAsOfUsecase    = "ProjectGetAsOfUsecase"
+	ProjectDiffUsecase       = "ProjectDiffUsecase"
+	ProjectRevertUsecase     = "ProjectRevertUsecase"
+	ProjectVersionRepository = "ProjectVersionRepository"
+	AuditRepository          = "AuditRepository"
+	ProjectRepository        = "ProjectRepository"
+	TaxCalculateUsecase      = "TaxCalculateUsecase"
+	TaxRateRepository        = "TaxRateRepository"
+	DBClient                 = "DBClient"
+	DBCircuitBreaker         = "DBCircuitBreaker"
+	EventBus                 = "EventBus"
+	TracerProvider           = "TracerProvider"
+	Metrics                  = "Metrics"
+	Logger                   = "Logger"
+)
+
+//List of consts containing the states of a component in the Application Context
+const (
+	//StateRegistered - the component was added but not initialized yet
+	StateRegistered = "REGISTERED"
+	//StateInitializing - the component initializer is running
+	StateInitializing = "INITIALIZING"
+	//StateInitialized - the component instance is available
+	StateInitialized = "INITIALIZED"
+	//StateFailed - the last initialization failed. The next Get retries it
+	StateFailed = "FAILED"
+	//StateStarted - the Lifecycle component was started
+	StateStarted = "STARTED"
+	//StateStopped - the Lifecycle component was stopped
+	StateStopped = "STOPPED"
 )
 
 //Component is the Base interface for all Components
@@ -20,45 +55,155 @@ type Component interface{}
 //ComponentInitializerFunction specifies a function for lazily initializing a component
 type ComponentInitializerFunction func() Component
 
+//ComponentFactoryFunction specifies a function for lazily initializing a component which reports the initialization failures
+type ComponentFactoryFunction func() (Component, error)
+
 //ComponentInfo holds the function to lazy initialize the component and the instance created following the singleton pattern
 type ComponentInfo struct {
-	Initializer 	ComponentInitializerFunction
-	Instance    	Component
+	Initializer  ComponentInitializerFunction
+	Factory      ComponentFactoryFunction
+	Dependencies []string
+	Instance     Component
+
+	locks   *componentLocks
+	state   string
+	err     error
+	started bool
+}
+
+//componentLocks synchronizes the access to a ComponentInfo
+type componentLocks struct {
+	//initialization guarantees the component is initialized only once, even by concurrent first calls
+	initialization sync.Mutex
+	//state guards Instance, state, err and started without waiting for a running initialization
+	state sync.Mutex
+}
+
+//unmanagedComponentLocks synchronizes the ComponentInfo instances created out of an ApplicationContext
+var unmanagedComponentLocks componentLocks
+
+func (componentInfo *ComponentInfo) componentLocks() *componentLocks {
+	if componentInfo.locks == nil {
+		return &unmanagedComponentLocks
+	}
+	return componentInfo.locks
 }
 
 //Get s the instance. If it is not created, creates and stores it to the next calls
 func (componentInfo *ComponentInfo) Get() Component {
+	instance, _, _ := componentInfo.initialize()
+	return instance
+}
 
-	if componentInfo.Instance == nil {
-		componentInfo.Instance = componentInfo.Initializer()
+//initialize creates the instance once, reporting if it was created by this call
+func (componentInfo *ComponentInfo) initialize() (Component, bool, error) {
+	if instance := componentInfo.instance(); instance != nil {
+		return instance, false, nil
 	}
 
+	locks := componentInfo.componentLocks()
+	locks.initialization.Lock()
+	defer locks.initialization.Unlock()
+	if instance := componentInfo.instance(); instance != nil {
+		return instance, false, nil
+	}
+
+	componentInfo.setState(StateInitializing, nil)
+	instance, err := componentInfo.factory()()
+	if err != nil {
+		componentInfo.setState(StateFailed, err)
+		return nil, false, err
+	}
+
+	locks.state.Lock()
+	defer locks.state.Unlock()
+	componentInfo.Instance = instance
+	componentInfo.state = StateInitialized
+	componentInfo.err = nil
+	return instance, instance != nil, nil
+}
+
+func (componentInfo *ComponentInfo) factory() ComponentFactoryFunction {
+	if componentInfo.Factory != nil {
+		return componentInfo.Factory
+	}
+	initializer := componentInfo.Initializer
+	return func() (Component, error) {
+		return initializer(), nil
+	}
+}
+
+func (componentInfo *ComponentInfo) instance() Component {
+	componentInfo.componentLocks().state.Lock()
+	defer componentInfo.componentLocks().state.Unlock()
+
 	return componentInfo.Instance
 }
 
+func (componentInfo *ComponentInfo) setState(state string, err error) {
+	componentInfo.componentLocks().state.Lock()
+	defer componentInfo.componentLocks().state.Unlock()
+
+	componentInfo.state = state
+	componentInfo.err = err
+}
+
 //ApplicationContext is the type defining a map of Components
 type ApplicationContext struct {
-	components map[string]*ComponentInfo
-	componentMutex sync.Mutex
+	components          map[string]*ComponentInfo
+	initializationOrder []string
+	decorators          map[string][]componentDecorator
+	profile             string
+	profiledComponents  []profiledComponent
+	componentMutex      sync.Mutex
 }
 
 //Current keeps all components available, initialized in the application startup
 var Current ApplicationContext
 
-//Add a component By Name
-func (applicationContext *ApplicationContext) Add(componentName string, componentInitializerFunction ComponentInitializerFunction) {
+//Add a component By Name, optionally declaring the names of the components it depends on
+func (applicationContext *ApplicationContext) Add(componentName string, componentInitializerFunction ComponentInitializerFunction, dependencies ...string) {
+	applicationContext.add(componentName, &ComponentInfo{Initializer: componentInitializerFunction, Dependencies: dependencies}, true)
+}
+
+//AddFactory adds a component By Name, which initialization may fail, optionally declaring the names of the components it depends on
+func (applicationContext *ApplicationContext) AddFactory(componentName string, componentFactoryFunction ComponentFactoryFunction, dependencies ...string) {
+	applicationContext.add(componentName, &ComponentInfo{Factory: componentFactoryFunction, Dependencies: dependencies}, true)
+}
+
+func (applicationContext *ApplicationContext) add(componentName string, componentInfo *ComponentInfo, decorated bool) {
 	applicationContext.componentMutex.Lock()
 	defer applicationContext.componentMutex.Unlock()
 
-	applicationContext.components[componentName] = &ComponentInfo{Initializer: componentInitializerFunction}
+	componentInfo.locks = &componentLocks{}
+	componentInfo.state = StateRegistered
+	if decorated {
+		for _, componentDecorator := range applicationContext.decorators[componentName] {
+			componentInfo.decorate(componentDecorator)
+		}
+	}
+	applicationContext.components[componentName] = componentInfo
+	applicationContext.removeFromInitializationOrder(componentName)
 }
 
 //Get a component By Name
 func (applicationContext *ApplicationContext) Get(componentName string) Component {
-	if applicationContext.components[componentName] == nil {
-		return nil
+	instance, _ := applicationContext.get(componentName)
+	return instance
+}
+
+func (applicationContext *ApplicationContext) get(componentName string) (Component, error) {
+	componentInfo := applicationContext.componentInfo(componentName)
+	if componentInfo == nil {
+		return nil, nil
+	}
+	instance, created, err := componentInfo.initialize()
+	if created {
+		applicationContext.componentMutex.Lock()
+		defer applicationContext.componentMutex.Unlock()
+		applicationContext.initializationOrder = append(applicationContext.initializationOrder, componentName)
 	}
-	return applicationContext.components[componentName].Get()
+	return instance, err
 }
 
 //Delete a component By Name
@@ -67,6 +212,17 @@ func (applicationContext *ApplicationContext) Delete(componentName string) {
 	defer applicationContext.componentMutex.Unlock()
 
 	delete(applicationContext.components, componentName)
+	applicationContext.removeFromInitializationOrder(componentName)
+}
+
+//removeFromInitializationOrder must be called holding the componentMutex
+func (applicationContext *ApplicationContext) removeFromInitializationOrder(componentName string) {
+	for index, initializedComponentName := range applicationContext.initializationOrder {
+		if initializedComponentName == componentName {
+			applicationContext.initializationOrder = append(applicationContext.initializationOrder[:index], applicationContext.initializationOrder[index+1:]...)
+			return
+		}
+	}
 }
 
 //Count returns the count of components registered
@@ -77,9 +233,16 @@ func (applicationContext *ApplicationContext) Count() int {
 	return len(applicationContext.components)
 }
 
+func (applicationContext *ApplicationContext) componentInfo(componentName string) *ComponentInfo {
+	applicationContext.componentMutex.Lock()
+	defer applicationContext.componentMutex.Unlock()
+
+	return applicationContext.components[componentName]
+}
+
 //CreateApplicationContext creates a new ApplicationContext instance
 func CreateApplicationContext() ApplicationContext {
-	return ApplicationContext{components: make(map[string]*ComponentInfo)}
+	return ApplicationContext{components: make(map[string]*ComponentInfo), decorators: make(map[string][]componentDecorator), profile: ProfileProduction}
 }
 
 func init() {
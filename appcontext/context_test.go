@@ -1,7 +1,13 @@
 package appcontext
 
 import (
+	stdcontext "context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestContext_Add(t *testing.T) {
@@ -49,3 +55,149 @@ func TestContext_Add(t *testing.T) {
 	}
 
 }
+
+type lifecycleRecorder struct {
+	name   string
+	events *[]string
+}
+
+func (recorder lifecycleRecorder) Start(ctx stdcontext.Context) error {
+	*recorder.events = append(*recorder.events, "start "+recorder.name)
+	return nil
+}
+
+func (recorder lifecycleRecorder) Stop(ctx stdcontext.Context) error {
+	*recorder.events = append(*recorder.events, "stop "+recorder.name)
+	return nil
+}
+
+func TestContext_Lifecycle(t *testing.T) {
+	events := make([]string, 0)
+	context := CreateApplicationContext()
+	context.Add(DBClient, func() Component { return lifecycleRecorder{name: DBClient, events: &events} })
+	context.Add(ProjectRepository, func() Component {
+		context.Get(DBClient)
+		return lifecycleRecorder{name: ProjectRepository, events: &events}
+	})
+	context.Get(ProjectRepository)
+
+	if err := context.StartAll(stdcontext.Background()); err != nil {
+		t.Errorf("Unexpected error starting the components: %s", err.Error())
+	}
+	if err := context.StopAll(stdcontext.Background()); err != nil {
+		t.Errorf("Unexpected error stopping the components: %s", err.Error())
+	}
+
+	expected := []string{"start " + DBClient, "start " + ProjectRepository, "stop " + ProjectRepository, "stop " + DBClient}
+	if strings.Join(events, ",") != strings.Join(expected, ",") {
+		t.Errorf("Components started and stopped out of the dependency order: %v", events)
+	}
+}
+
+func TestContext_GetInitializesOnce(t *testing.T) {
+	context := CreateApplicationContext()
+	var initializations int32
+	context.Add(DBClient, func() Component {
+		atomic.AddInt32(&initializations, 1)
+		time.Sleep(10 * time.Millisecond)
+		return &ComponentInfo{}
+	})
+
+	var waitGroup sync.WaitGroup
+	instances := make([]Component, 10)
+	for index := range instances {
+		waitGroup.Add(1)
+		go func(index int) {
+			defer waitGroup.Done()
+			instances[index] = context.Get(DBClient)
+		}(index)
+	}
+	waitGroup.Wait()
+
+	if initializations != 1 {
+		t.Errorf("Component initialized %d times", initializations)
+	}
+	for _, instance := range instances {
+		if instance != instances[0] {
+			t.Error("Different instances returned for the same component")
+		}
+	}
+}
+
+func TestContext_InitializeAll(t *testing.T) {
+	tests := []struct {
+		name           string
+		register       func(context *ApplicationContext)
+		expectedErrors []string
+	}{
+		{
+			name: "All initialized",
+			register: func(context *ApplicationContext) {
+				context.Add(Logger, func() Component { return "logger" })
+				context.Add(DBClient, func() Component { return "db" }, Logger)
+			},
+		},
+		{
+			name: "Missing dependency",
+			register: func(context *ApplicationContext) {
+				context.Add(ProjectRepository, func() Component { return "repository" }, DBClient)
+			},
+			expectedErrors: []string{"The component ProjectRepository depends on DBClient, which is not registered"},
+		},
+		{
+			name: "Cyclic dependency",
+			register: func(context *ApplicationContext) {
+				context.Add(DBClient, func() Component { return "db" }, ProjectRepository)
+				context.Add(ProjectRepository, func() Component { return "repository" }, DBClient)
+			},
+			expectedErrors: []string{"Cyclic dependency between the components: DBClient -> ProjectRepository -> DBClient"},
+		},
+		{
+			name: "Every failure reported",
+			register: func(context *ApplicationContext) {
+				context.AddFactory(DBClient, func() (Component, error) { return nil, errors.New("connection refused") })
+				context.AddFactory(Metrics, func() (Component, error) { return nil, errors.New("invalid namespace") })
+				context.Add(ProjectRepository, func() Component { return "repository" }, DBClient)
+			},
+			expectedErrors: []string{
+				"Could not initialize the component DBClient: connection refused",
+				"Could not initialize the component Metrics: invalid namespace",
+				"The component ProjectRepository was not initialized because its dependency DBClient failed",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			context := CreateApplicationContext()
+			tt.register(&context)
+			err := context.InitializeAll()
+			if len(tt.expectedErrors) == 0 && err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			for _, expectedError := range tt.expectedErrors {
+				if err == nil || !strings.Contains(err.Error(), expectedError) {
+					t.Errorf("Expected error %q, got %v", expectedError, err)
+				}
+			}
+		})
+	}
+}
+
+func TestContext_Components(t *testing.T) {
+	context := CreateApplicationContext()
+	context.Add(Logger, func() Component { return "logger" })
+	context.AddFactory(DBClient, func() (Component, error) { return nil, errors.New("connection refused") }, Logger)
+	context.Get(Logger)
+	context.Get(DBClient)
+
+	components := context.Components()
+	if len(components) != 2 {
+		t.Fatalf("Expected 2 components, got %d", len(components))
+	}
+	if components[0].Name != DBClient || components[0].State != StateFailed || components[0].Error == nil {
+		t.Errorf("Unexpected DBClient status: %+v", components[0])
+	}
+	if components[1].Name != Logger || components[1].State != StateInitialized {
+		t.Errorf("Unexpected Logger status: %+v", components[1])
+	}
+}
@@ -0,0 +1,37 @@
+package appcontext
+
+//ComponentDecoratorFunction wraps the instance of a component (e.g. adding a cache to a Repository), which is replaced by the returned Component
+type ComponentDecoratorFunction func(component Component) (Component, error)
+
+//componentDecorator keeps a decorator with the components it depends on
+type componentDecorator struct {
+	decorator    ComponentDecoratorFunction
+	dependencies []string
+}
+
+//Decorate wraps the component By Name with the decorator, no matter if it is added before or after the decorator, optionally declaring the
+//names of the components the decorator depends on. It must be called before the component is initialized. Overridden components are not decorated
+func (applicationContext *ApplicationContext) Decorate(componentName string, decorator ComponentDecoratorFunction, dependencies ...string) {
+	applicationContext.componentMutex.Lock()
+	defer applicationContext.componentMutex.Unlock()
+
+	componentDecorator := componentDecorator{decorator: decorator, dependencies: dependencies}
+	applicationContext.decorators[componentName] = append(applicationContext.decorators[componentName], componentDecorator)
+	if componentInfo, ok := applicationContext.components[componentName]; ok {
+		componentInfo.decorate(componentDecorator)
+	}
+}
+
+//decorate chains the decorator to the factory of the component, which also depends on the decorator dependencies
+func (componentInfo *ComponentInfo) decorate(componentDecorator componentDecorator) {
+	factory := componentInfo.factory()
+	componentInfo.Initializer = nil
+	componentInfo.Factory = func() (Component, error) {
+		instance, err := factory()
+		if err != nil {
+			return nil, err
+		}
+		return componentDecorator.decorator(instance)
+	}
+	componentInfo.Dependencies = append(append([]string{}, componentInfo.Dependencies...), componentDecorator.dependencies...)
+}
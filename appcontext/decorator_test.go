@@ -0,0 +1,51 @@
+package appcontext
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestContext_Decorate(t *testing.T) {
+	context := CreateApplicationContext()
+	context.Decorate(ProjectRepository, func(component Component) (Component, error) {
+		return "cached " + component.(string), nil
+	}, Metrics)
+	context.Add(ProjectRepository, func() Component { return "mongodb" }, DBClient)
+
+	if repository := context.Get(ProjectRepository); repository != "cached mongodb" {
+		t.Errorf("Expected the component added after the decorator to be decorated, got %v", repository)
+	}
+	if dependencies := context.componentInfo(ProjectRepository).Dependencies; len(dependencies) != 2 || dependencies[1] != Metrics {
+		t.Errorf("Expected the decorator dependencies to be added, got %v", dependencies)
+	}
+}
+
+func TestContext_DecorateFailure(t *testing.T) {
+	context := CreateApplicationContext()
+	context.Add(ProjectRepository, func() Component { return "mongodb" })
+	context.Decorate(ProjectRepository, func(component Component) (Component, error) {
+		return nil, errors.New("decorator failed")
+	})
+
+	if err := context.InitializeAll(); err == nil {
+		t.Error("Expected the decorator failure to be reported")
+	}
+}
+
+func TestContext_DecorateAddedComponent(t *testing.T) {
+	context := CreateApplicationContext()
+	context.AddFactory(ProjectRepository, func() (Component, error) { return "mongodb", nil })
+	context.Decorate(ProjectRepository, func(component Component) (Component, error) {
+		return "cached " + component.(string), nil
+	})
+
+	if repository := context.Get(ProjectRepository); repository != "cached mongodb" {
+		t.Errorf("Expected the component added before the decorator to be decorated, got %v", repository)
+	}
+
+	restore := context.Override(ProjectRepository, "fake")
+	defer restore()
+	if repository := context.Get(ProjectRepository); repository != "fake" {
+		t.Errorf("Expected the overridden component not to be decorated, got %v", repository)
+	}
+}
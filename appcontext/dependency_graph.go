@@ -0,0 +1,143 @@
+package appcontext
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//ComponentStatus describes a registered component and its current state
+type ComponentStatus struct {
+	Name         string
+	State        string
+	Dependencies []string
+	Error        error
+}
+
+//Validate checks that every declared dependency is registered and that there are no dependency cycles
+func (applicationContext *ApplicationContext) Validate() error {
+	components := applicationContext.registeredComponents()
+	var errs []error
+	for _, componentName := range sortedNames(components) {
+		for _, dependency := range components[componentName].Dependencies {
+			if _, ok := components[dependency]; !ok {
+				errs = append(errs, fmt.Errorf("The component %s depends on %s, which is not registered", componentName, dependency))
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[string]int)
+	path := make([]string, 0)
+	var visit func(componentName string)
+	visit = func(componentName string) {
+		switch marks[componentName] {
+		case visited:
+			return
+		case visiting:
+			cycleStart := 0
+			for index, pathComponentName := range path {
+				if pathComponentName == componentName {
+					cycleStart = index
+				}
+			}
+			cycle := append(append([]string(nil), path[cycleStart:]...), componentName)
+			errs = append(errs, fmt.Errorf("Cyclic dependency between the components: %s", strings.Join(cycle, " -> ")))
+			return
+		}
+		marks[componentName] = visiting
+		path = append(path, componentName)
+		for _, dependency := range components[componentName].Dependencies {
+			if _, ok := components[dependency]; ok {
+				visit(dependency)
+			}
+		}
+		path = path[:len(path)-1]
+		marks[componentName] = visited
+	}
+	for _, componentName := range sortedNames(components) {
+		if marks[componentName] == unvisited {
+			visit(componentName)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+//InitializeAll eagerly initializes every registered component, dependencies first, reporting all the failures instead of stopping at the first one
+func (applicationContext *ApplicationContext) InitializeAll() error {
+	if err := applicationContext.Validate(); err != nil {
+		return err
+	}
+
+	components := applicationContext.registeredComponents()
+	failed := make(map[string]bool)
+	done := make(map[string]bool)
+	var errs []error
+	var initialize func(componentName string)
+	initialize = func(componentName string) {
+		if done[componentName] {
+			return
+		}
+		done[componentName] = true
+		for _, dependency := range components[componentName].Dependencies {
+			initialize(dependency)
+			if failed[dependency] {
+				failed[componentName] = true
+				errs = append(errs, fmt.Errorf("The component %s was not initialized because its dependency %s failed", componentName, dependency))
+				return
+			}
+		}
+		if _, err := applicationContext.get(componentName); err != nil {
+			failed[componentName] = true
+			errs = append(errs, fmt.Errorf("Could not initialize the component %s: %w", componentName, err))
+		}
+	}
+	for _, componentName := range sortedNames(components) {
+		initialize(componentName)
+	}
+	return errors.Join(errs...)
+}
+
+//Components lists the registered components and their current state, ordered by name
+func (applicationContext *ApplicationContext) Components() []ComponentStatus {
+	components := applicationContext.registeredComponents()
+	componentStatusList := make([]ComponentStatus, 0, len(components))
+	for _, componentName := range sortedNames(components) {
+		componentInfo := components[componentName]
+		componentInfo.componentLocks().state.Lock()
+		componentStatusList = append(componentStatusList, ComponentStatus{
+			Name:         componentName,
+			State:        componentInfo.state,
+			Dependencies: append([]string(nil), componentInfo.Dependencies...),
+			Error:        componentInfo.err,
+		})
+		componentInfo.componentLocks().state.Unlock()
+	}
+	return componentStatusList
+}
+
+//registeredComponents returns a snapshot of the components map
+func (applicationContext *ApplicationContext) registeredComponents() map[string]*ComponentInfo {
+	applicationContext.componentMutex.Lock()
+	defer applicationContext.componentMutex.Unlock()
+
+	components := make(map[string]*ComponentInfo, len(applicationContext.components))
+	for componentName, componentInfo := range applicationContext.components {
+		components[componentName] = componentInfo
+	}
+	return components
+}
+
+func sortedNames(components map[string]*ComponentInfo) []string {
+	componentNames := make([]string, 0, len(components))
+	for componentName := range components {
+		componentNames = append(componentNames, componentName)
+	}
+	sort.Strings(componentNames)
+	return componentNames
+}
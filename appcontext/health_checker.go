@@ -0,0 +1,26 @@
+package appcontext
+
+import "context"
+
+//HealthChecker is implemented by the Components which check the health of a dependency (e.g. the DB connection) for the readiness probe
+type HealthChecker interface {
+	//CheckHealth returns an error when the Component can not serve requests
+	CheckHealth(ctx context.Context) error
+}
+
+//StateReporter is implemented by the HealthCheckers which report their state (e.g. the state of a circuit breaker) besides UP or DOWN
+type StateReporter interface {
+	//State returns the current state of the Component
+	State() string
+}
+
+//HealthCheckers returns the already initialized Components which implement HealthChecker, by Component name
+func (applicationContext *ApplicationContext) HealthCheckers() map[string]HealthChecker {
+	healthCheckers := make(map[string]HealthChecker)
+	for componentName, componentInfo := range applicationContext.registeredComponents() {
+		if healthChecker, ok := componentInfo.instance().(HealthChecker); ok {
+			healthCheckers[componentName] = healthChecker
+		}
+	}
+	return healthCheckers
+}
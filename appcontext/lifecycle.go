@@ -0,0 +1,83 @@
+package appcontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+//Lifecycle is implemented by the Components which hold resources (connections, background workers, buffers) to be started and released with the application
+type Lifecycle interface {
+	//Start the Component. Called once, after the Component is initialized
+	Start(ctx context.Context) error
+	//Stop the Component releasing its resources. Called once, during the application shutdown
+	Stop(ctx context.Context) error
+}
+
+//StartAll starts the initialized Lifecycle Components, in the initialization order, which were not started yet
+func (applicationContext *ApplicationContext) StartAll(ctx context.Context) error {
+	var errs []error
+	for _, componentName := range applicationContext.initializedComponents() {
+		componentInfo := applicationContext.componentInfo(componentName)
+		if componentInfo == nil {
+			continue
+		}
+		lifecycle, ok := componentInfo.instance().(Lifecycle)
+		if !ok || componentInfo.isStarted() {
+			continue
+		}
+		if err := lifecycle.Start(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("Could not start the component %s: %w", componentName, err))
+			continue
+		}
+		componentInfo.setStarted(true)
+	}
+	return errors.Join(errs...)
+}
+
+//StopAll stops the started Lifecycle Components in the reverse initialization order, so each Component is stopped before its dependencies
+func (applicationContext *ApplicationContext) StopAll(ctx context.Context) error {
+	var errs []error
+	componentNames := applicationContext.initializedComponents()
+	for index := len(componentNames) - 1; index >= 0; index-- {
+		componentInfo := applicationContext.componentInfo(componentNames[index])
+		if componentInfo == nil {
+			continue
+		}
+		lifecycle, ok := componentInfo.instance().(Lifecycle)
+		if !ok || !componentInfo.isStarted() {
+			continue
+		}
+		if err := lifecycle.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("Could not stop the component %s: %w", componentNames[index], err))
+		}
+		componentInfo.setStarted(false)
+	}
+	return errors.Join(errs...)
+}
+
+func (applicationContext *ApplicationContext) initializedComponents() []string {
+	applicationContext.componentMutex.Lock()
+	defer applicationContext.componentMutex.Unlock()
+
+	return append([]string(nil), applicationContext.initializationOrder...)
+}
+
+func (componentInfo *ComponentInfo) isStarted() bool {
+	componentInfo.componentLocks().state.Lock()
+	defer componentInfo.componentLocks().state.Unlock()
+
+	return componentInfo.started
+}
+
+func (componentInfo *ComponentInfo) setStarted(started bool) {
+	componentInfo.componentLocks().state.Lock()
+	defer componentInfo.componentLocks().state.Unlock()
+
+	componentInfo.started = started
+	if started {
+		componentInfo.state = StateStarted
+	} else {
+		componentInfo.state = StateStopped
+	}
+}
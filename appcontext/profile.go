@@ -0,0 +1,141 @@
+package appcontext
+
+//List of consts containing the profiles the components can be registered for
+const (
+	//ProfileProduction is the default profile, wiring the real infrastructure (e.g. MongoDB)
+	ProfileProduction = "production"
+	//ProfileLocal is used for running the application in the developer machine
+	ProfileLocal = "local"
+	//ProfileTest is active while running the tests, which register fakes for the infrastructure components
+	ProfileTest = "test"
+)
+
+//SetProfile activates the profile. The components registered for other profiles are removed and the ones registered for it are added again,
+//so the tests can activate the test profile after the components were added by the init functions
+func (applicationContext *ApplicationContext) SetProfile(profile string) {
+	applicationContext.componentMutex.Lock()
+	applicationContext.profile = profile
+	profiledComponents := append([]profiledComponent(nil), applicationContext.profiledComponents...)
+	applicationContext.componentMutex.Unlock()
+
+	for _, component := range profiledComponents {
+		applicationContext.Delete(component.componentName)
+	}
+	for _, component := range profiledComponents {
+		if component.activeFor(profile) {
+			applicationContext.add(component.componentName, component.componentInfo(), true)
+		}
+	}
+}
+
+//Profile returns the active profile
+func (applicationContext *ApplicationContext) Profile() string {
+	applicationContext.componentMutex.Lock()
+	defer applicationContext.componentMutex.Unlock()
+
+	return applicationContext.profile
+}
+
+//AddForProfiles adds a component By Name only if any of the profiles is active, allowing alternative implementations per profile
+func (applicationContext *ApplicationContext) AddForProfiles(profiles []string, componentName string, componentInitializerFunction ComponentInitializerFunction, dependencies ...string) {
+	applicationContext.addForProfiles(profiledComponent{profiles: profiles, componentName: componentName, initializer: componentInitializerFunction, dependencies: dependencies})
+}
+
+//AddFactoryForProfiles adds a component By Name, which initialization may fail, only if any of the profiles is active
+func (applicationContext *ApplicationContext) AddFactoryForProfiles(profiles []string, componentName string, componentFactoryFunction ComponentFactoryFunction, dependencies ...string) {
+	applicationContext.addForProfiles(profiledComponent{profiles: profiles, componentName: componentName, factory: componentFactoryFunction, dependencies: dependencies})
+}
+
+//profiledComponent is a component registered for some profiles, kept for registering it again when the active profile changes
+type profiledComponent struct {
+	profiles      []string
+	componentName string
+	initializer   ComponentInitializerFunction
+	factory       ComponentFactoryFunction
+	dependencies  []string
+}
+
+func (component profiledComponent) componentInfo() *ComponentInfo {
+	return &ComponentInfo{Initializer: component.initializer, Factory: component.factory, Dependencies: component.dependencies}
+}
+
+func (component profiledComponent) activeFor(activeProfile string) bool {
+	for _, profile := range component.profiles {
+		if profile == activeProfile {
+			return true
+		}
+	}
+	return false
+}
+
+func (applicationContext *ApplicationContext) addForProfiles(component profiledComponent) {
+	applicationContext.componentMutex.Lock()
+	applicationContext.profiledComponents = append(applicationContext.profiledComponents, component)
+	activeProfile := applicationContext.profile
+	applicationContext.componentMutex.Unlock()
+
+	if component.activeFor(activeProfile) {
+		applicationContext.add(component.componentName, component.componentInfo(), true)
+	}
+}
+
+//Override replaces the component By Name with the provided instance (e.g. a fake in a test), resetting the components which depend on it.
+//The returned function restores the previous component
+func (applicationContext *ApplicationContext) Override(componentName string, component Component) func() {
+	previous := applicationContext.componentInfo(componentName)
+	applicationContext.add(componentName, &ComponentInfo{Initializer: func() Component { return component }}, false)
+	applicationContext.resetDependents(componentName)
+
+	return func() {
+		if previous == nil {
+			applicationContext.Delete(componentName)
+		} else {
+			applicationContext.componentMutex.Lock()
+			applicationContext.components[componentName] = previous
+			applicationContext.componentMutex.Unlock()
+		}
+		applicationContext.resetDependents(componentName)
+	}
+}
+
+//resetDependents discards the instances of the components which depend, directly or transitively, on the component, so they are initialized again
+func (applicationContext *ApplicationContext) resetDependents(componentName string) {
+	components := applicationContext.registeredComponents()
+	reset := map[string]bool{componentName: true}
+	for changed := true; changed; {
+		changed = false
+		for dependentName, componentInfo := range components {
+			if reset[dependentName] {
+				continue
+			}
+			for _, dependency := range componentInfo.Dependencies {
+				if reset[dependency] {
+					reset[dependentName] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	applicationContext.componentMutex.Lock()
+	defer applicationContext.componentMutex.Unlock()
+	for dependentName := range reset {
+		if dependentName == componentName {
+			continue
+		}
+		components[dependentName].reset()
+		applicationContext.removeFromInitializationOrder(dependentName)
+	}
+}
+
+//reset discards the instance, so the component is initialized again by the next Get
+func (componentInfo *ComponentInfo) reset() {
+	componentInfo.componentLocks().state.Lock()
+	defer componentInfo.componentLocks().state.Unlock()
+
+	componentInfo.Instance = nil
+	componentInfo.state = StateRegistered
+	componentInfo.err = nil
+	componentInfo.started = false
+}
@@ -0,0 +1,52 @@
+package appcontext
+
+import (
+	"testing"
+)
+
+func TestContext_AddForProfiles(t *testing.T) {
+	context := CreateApplicationContext()
+	context.SetProfile(ProfileTest)
+	context.AddForProfiles([]string{ProfileProduction, ProfileLocal}, ProjectRepository, func() Component { return "mongodb" })
+	context.AddForProfiles([]string{ProfileTest}, ProjectRepository, func() Component { return "fake" })
+
+	if repository := context.Get(ProjectRepository); repository != "fake" {
+		t.Errorf("Expected the test profile component, got %v", repository)
+	}
+}
+
+func TestContext_Override(t *testing.T) {
+	context := CreateApplicationContext()
+	context.Add(ProjectRepository, func() Component { return "mongodb" })
+	context.Add(ProjectGetByIDUsecase, func() Component { return "usecase with " + context.Get(ProjectRepository).(string) }, ProjectRepository)
+	if usecase := context.Get(ProjectGetByIDUsecase); usecase != "usecase with mongodb" {
+		t.Fatalf("Unexpected usecase %v", usecase)
+	}
+
+	restore := context.Override(ProjectRepository, "fake")
+	if usecase := context.Get(ProjectGetByIDUsecase); usecase != "usecase with fake" {
+		t.Errorf("Dependent component not reset by the Override, got %v", usecase)
+	}
+
+	restore()
+	if usecase := context.Get(ProjectGetByIDUsecase); usecase != "usecase with mongodb" {
+		t.Errorf("Dependent component not reset by the restore, got %v", usecase)
+	}
+}
+
+func TestContext_SetProfileAfterAddForProfiles(t *testing.T) {
+	context := CreateApplicationContext()
+	context.AddForProfiles([]string{ProfileProduction, ProfileLocal}, DBClient, func() Component { return "mongodb" })
+	context.AddForProfiles([]string{ProfileTest}, ProjectRepository, func() Component { return "fake" })
+	if client := context.Get(DBClient); client != "mongodb" {
+		t.Fatalf("Expected the production profile component, got %v", client)
+	}
+
+	context.SetProfile(ProfileTest)
+	if client := context.Get(DBClient); client != nil {
+		t.Errorf("Expected the production profile component to be removed, got %v", client)
+	}
+	if repository := context.Get(ProjectRepository); repository != "fake" {
+		t.Errorf("Expected the test profile component, got %v", repository)
+	}
+}
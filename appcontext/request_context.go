@@ -0,0 +1,51 @@
+package appcontext
+
+import "context"
+
+type requestIDKey struct{}
+
+type actorKey struct{}
+
+type actorVerifiedKey struct{}
+
+//WithRequestID returns a copy of the request scoped context carrying the Request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+//GetRequestID returns the Request ID carried by the context or an empty string if there is none
+func GetRequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+//WithActor returns a copy of the request scoped context carrying the actor (e.g. the user) who made the request, as informed by the client
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+//WithVerifiedActor returns a copy of the request scoped context carrying the actor authenticated by a trusted party (e.g. the gateway)
+func WithVerifiedActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(WithActor(ctx, actor), actorVerifiedKey{}, true)
+}
+
+//GetActor returns the actor carried by the context or an empty string if there is none
+func GetActor(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+//IsActorVerified reports if the actor carried by the context was authenticated
+func IsActorVerified(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	verified, _ := ctx.Value(actorVerifiedKey{}).(bool)
+	return verified
+}
@@ -1,6 +1,11 @@
 package config
 
-import "github.com/danilovalente/project-api/appcontext"
+import (
+	"context"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"go.opentelemetry.io/otel/trace"
+)
 
 // Logger interface defining expected methods for logging
 type Logger interface {
@@ -48,6 +53,9 @@ type Logger interface {
 	// Fatalf uses fmt.Sprintf to log a templated message, then calls os.Exit.
 	Fatalf(template string, args ...interface{})
 
+	//With adds structured context (key-value pairs) to the returned Logger
+	With(args ...interface{}) Logger
+
 	//Sync flushes the log if needed
 	Sync()
 }
@@ -56,3 +64,22 @@ type Logger interface {
 func GetLogger() Logger {
 	return appcontext.Current.Get(appcontext.Logger).(Logger)
 }
+
+//GetContextLogger s Current implementation enriched with the request scoped values carried by the context (Request ID, Trace ID and Span ID)
+func GetContextLogger(ctx context.Context) Logger {
+	logger := GetLogger()
+	if ctx == nil {
+		return logger
+	}
+	fields := make([]interface{}, 0, 6)
+	if requestID := appcontext.GetRequestID(ctx); requestID != "" {
+		fields = append(fields, "requestId", requestID)
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		fields = append(fields, "traceId", spanContext.TraceID().String(), "spanId", spanContext.SpanID().String())
+	}
+	if len(fields) == 0 {
+		return logger
+	}
+	return logger.With(fields...)
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+)
+
+// Metrics interface defining expected methods for recording the application metrics
+type Metrics interface {
+	//ProjectChanged counts a Project created, updated or deleted by its currency and time unit
+	ProjectChanged(operation string, currency string, timeUnit string)
+
+	//ObserveUsecase records the latency and the outcome of a Usecase execution
+	ObserveUsecase(usecase string, duration time.Duration, err error)
+
+	//ObserveRepository records the latency and the outcome of a Repository method
+	ObserveRepository(repository string, method string, duration time.Duration, err error)
+
+	//ConnectionPoolChanged moves the DB connection pool gauge of the provided state (e.g. open, in_use) by delta
+	ConnectionPoolChanged(state string, delta float64)
+
+	//ConnectionPoolEvent counts a DB connection pool event (e.g. checkout failed, pool cleared)
+	ConnectionPoolEvent(event string)
+
+	//ObserveCache counts a cache lookup as a hit or a miss
+	ObserveCache(cache string, hit bool)
+
+	//ObserveRetry counts a DB operation retried after a transient error
+	ObserveRetry(operation string)
+
+	//CircuitBreakerChanged records the current state (closed, open or half-open) of the circuit breaker
+	CircuitBreakerChanged(breaker string, state string)
+}
+
+// noopMetrics is used while no Metrics implementation is registered (e.g. in the tests)
+type noopMetrics struct{}
+
+func (noopMetrics) ProjectChanged(string, string, string)                  {}
+func (noopMetrics) ObserveUsecase(string, time.Duration, error)            {}
+func (noopMetrics) ObserveRepository(string, string, time.Duration, error) {}
+func (noopMetrics) ConnectionPoolChanged(string, float64)                  {}
+func (noopMetrics) ConnectionPoolEvent(string)                             {}
+func (noopMetrics) ObserveCache(string, bool)                              {}
+func (noopMetrics) ObserveRetry(string)                                    {}
+func (noopMetrics) CircuitBreakerChanged(string, string)                   {}
+
+// GetMetrics s Current implementation
+func GetMetrics() Metrics {
+	metrics, ok := appcontext.Current.Get(appcontext.Metrics).(Metrics)
+	if !ok {
+		return noopMetrics{}
+	}
+	return metrics
+}
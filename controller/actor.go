@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"crypto/subtle"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/labstack/echo/v4"
+)
+
+//HeaderXUser identifies the actor (e.g. the user) who made the request, recorded with the changes
+const HeaderXUser = "X-User"
+
+//HeaderXActorToken carries the ActorToken shared with the authenticating gateway which sets the X-User header
+const HeaderXActorToken = "X-Actor-Token"
+
+//Actor middleware stores the actor of the X-User header in the request scoped context. Without a valid header, the changes are made by the domain.AnonymousActor.
+//The actor is verified only when the request carries the configured ActorToken, otherwise it is recorded as informed by the client
+func Actor(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := c.Request()
+		//The actor is also logged and stored, so it follows the same rules of the Request ID
+		if actor := request.Header.Get(HeaderXUser); validRequestID(actor) {
+			if verifiedActor(request.Header.Get(HeaderXActorToken)) {
+				c.SetRequest(request.WithContext(appcontext.WithVerifiedActor(request.Context(), actor)))
+			} else {
+				c.SetRequest(request.WithContext(appcontext.WithActor(request.Context(), actor)))
+			}
+		}
+		return next(c)
+	}
+}
+
+//verifiedActor checks the token against the ActorToken. Without an ActorToken no actor is verified
+func verifiedActor(token string) bool {
+	actorToken := config.Values.ActorToken
+	return actorToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(actorToken)) == 1
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActor(t *testing.T) {
+	tests := []struct {
+		name             string
+		header           string
+		token            string
+		expectedActor    string
+		expectedVerified bool
+	}{
+		{name: "Informed", header: "alice", expectedActor: "alice"},
+		{name: "Verified", header: "alice", token: "secret", expectedActor: "alice", expectedVerified: true},
+		{name: "Invalid Token", header: "alice", token: "guess", expectedActor: "alice"},
+		{name: "Missing", token: "secret", expectedActor: domain.AnonymousActor},
+		{name: "Invalid", header: "forged\nlog line", expectedActor: domain.AnonymousActor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			previous := config.Values.ActorToken
+			config.Values.ActorToken = "secret"
+			defer func() { config.Values.ActorToken = previous }()
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderXUser, tt.header)
+			}
+			if tt.token != "" {
+				req.Header.Set(HeaderXActorToken, tt.token)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			var contextActor string
+			var contextVerified bool
+			h := Actor(func(c echo.Context) error {
+				contextActor = domain.Actor(c.Request().Context())
+				contextVerified = domain.ActorVerified(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+
+			// Assertions
+			handle(c, h)
+			assert.Equal(t, tt.expectedActor, contextActor)
+			assert.Equal(t, tt.expectedVerified, contextVerified)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/danilovalente/project-api/config"
+	"github.com/labstack/echo/v4"
+)
+
+//bearerScheme prefixes the token in the Authorization header
+const bearerScheme = "Bearer "
+
+//AdminAuth middleware requires the configured AdminToken as the Bearer token of the Authorization header.
+//Without an AdminToken the admin endpoints are disabled, answering 404 as if they did not exist
+func AdminAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		adminToken := config.Values.AdminToken
+		if adminToken == "" {
+			return echo.ErrNotFound
+		}
+		authorization := c.Request().Header.Get(echo.HeaderAuthorization)
+		if !strings.HasPrefix(authorization, bearerScheme) {
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, strings.TrimSpace(bearerScheme))
+			return echo.NewHTTPError(http.StatusUnauthorized, "Missing the admin Bearer token")
+		}
+		token := strings.TrimPrefix(authorization, bearerScheme)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			return echo.NewHTTPError(http.StatusForbidden, "Invalid admin token")
+		}
+		return next(c)
+	}
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danilovalente/project-api/config"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name           string
+		adminToken     string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "Authorized", adminToken: "secret", authorization: "Bearer secret", expectedStatus: http.StatusOK},
+		{name: "Missing Token", adminToken: "secret", expectedStatus: http.StatusUnauthorized},
+		{name: "Other Scheme", adminToken: "secret", authorization: "Basic secret", expectedStatus: http.StatusUnauthorized},
+		{name: "Invalid Token", adminToken: "secret", authorization: "Bearer guess", expectedStatus: http.StatusForbidden},
+		{name: "Disabled", authorization: "Bearer ", expectedStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			previous := config.Values.AdminToken
+			config.Values.AdminToken = tt.adminToken
+			defer func() { config.Values.AdminToken = previous }()
+			e := echo.New()
+			e.HTTPErrorHandler = HTTPErrorHandler
+			e.Group("/admin", AdminAuth).GET("/components", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/admin/components", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			// Assertions
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"github.com/labstack/echo/v4"
+)
+
+//GetComponents lists the components registered in the Application Context and their state
+func GetComponents(c echo.Context) error {
+	componentStatusList := appcontext.Current.Components()
+	components := make([]domain.Component, 0, len(componentStatusList))
+	for _, componentStatus := range componentStatusList {
+		component := domain.Component{
+			Name:         componentStatus.Name,
+			State:        componentStatus.State,
+			Dependencies: componentStatus.Dependencies,
+		}
+		if componentStatus.Error != nil {
+			component.Error = componentStatus.Error.Error()
+		}
+		components = append(components, component)
+	}
+	return c.JSON(http.StatusOK, components)
+}
+
+//SearchAudit finds the changes of every entity, filtered by entity, entityId, actor and the RFC 3339 dates from (inclusive) and until (exclusive)
+func SearchAudit(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+	lastEntryID, pageSize, err := pageParams(c, "lastEntryId")
+	if err != nil {
+		return err
+	}
+	query := domain.AuditQuery{
+		Entity:      c.QueryParam("entity"),
+		EntityID:    c.QueryParam("entityId"),
+		Actor:       c.QueryParam("actor"),
+		LastEntryID: lastEntryID,
+		PageSize:    pageSize,
+	}
+	violations := domain.Violations{}
+	query.From = timeParam(c, "from", &violations)
+	query.Until = timeParam(c, "until", &violations)
+	if err := violations.Err("auditQuery.invalid"); err != nil {
+		return err
+	}
+
+	entries, err := domain.GetAuditSearchUsecase().Execute(ctx, query)
+	if err != nil {
+		logger.Errorf("An error occurred while trying to search the audit entries: %s", err.Error())
+		return err
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+//timeParam reads the optional RFC 3339 date of the query param, adding a violation if it is invalid
+func timeParam(c echo.Context, name string, violations *domain.Violations) time.Time {
+	value := c.QueryParam(name)
+	if value == "" {
+		return time.Time{}
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		violations.Add(name, domain.ViolationFormat, name, value)
+	}
+	return date
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetComponents(t *testing.T) {
+	// Setup
+	appcontext.Current.Add("FakeComponent", func() appcontext.Component { return "fake" }, appcontext.Logger)
+	defer appcontext.Current.Delete("FakeComponent")
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/project-api/v1/admin/components")
+
+	// Assertions
+	handle(c, GetComponents)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	components := make([]domain.Component, 0)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &components))
+	assert.Contains(t, components, domain.Component{
+		Name:         "FakeComponent",
+		State:        appcontext.StateRegistered,
+		Dependencies: []string{appcontext.Logger},
+	})
+}
+
+func TestSearchAudit(t *testing.T) {
+	// Setup
+	repo := useMemoryProjectRepository(t)
+	alice, bob := newProject(), newProject()
+	alice.ID, bob.ID = primitive.NilObjectID, primitive.NilObjectID
+	bob.Name = "Other"
+	_, _ = repo.Save(appcontext.WithActor(context.Background(), "alice"), &alice)
+	_, _ = repo.Save(appcontext.WithActor(context.Background(), "bob"), &bob)
+	tests := []struct {
+		name          string
+		query         string
+		expectedCode  int
+		expectedCount int
+	}{
+		{name: "All", query: "", expectedCode: http.StatusOK, expectedCount: 2},
+		{name: "By actor", query: "?actor=bob", expectedCode: http.StatusOK, expectedCount: 1},
+		{name: "By entity", query: "?entity=project&entityId=" + alice.ID.Hex(), expectedCode: http.StatusOK, expectedCount: 1},
+		{name: "By date", query: "?from=2000-01-01T00:00:00Z&until=2000-01-02T00:00:00Z", expectedCode: http.StatusOK, expectedCount: 0},
+		{name: "Invalid date", query: "?from=yesterday", expectedCode: http.StatusBadRequest},
+		{name: "Invalid last entry", query: "?lastEntryId=invalid", expectedCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/admin/audit")
+
+			// Assertions
+			handle(c, SearchAudit)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				response := make([]domain.AuditEntry, 0)
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Len(t, response, tt.expectedCount)
+			}
+		})
+	}
+}
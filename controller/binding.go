@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/danilovalente/project-api/domain"
+	"github.com/labstack/echo/v4"
+)
+
+//bind reads the request body into the target, describing the binding failures as field violations
+func bind(c echo.Context, target interface{}) error {
+	if err := c.Bind(target); err != nil {
+		return domain.ConstraintViolations("request.unreadable", bindingViolation(err))
+	}
+	return nil
+}
+
+//bindingViolation translates the error of the Echo binder, which keeps the decoding error as the internal one.
+//The violations reported by the domain decoders (e.g. QuoteQuantity) are kept as they are.
+func bindingViolation(err error) domain.Violation {
+	if httpError, ok := err.(*echo.HTTPError); ok && httpError.Internal != nil {
+		err = httpError.Internal
+	}
+	var violation domain.ConstraintViolationError
+	if errors.As(err, &violation) && len(violation.Violations) == 1 {
+		return violation.Violations[0]
+	}
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return domain.NewViolation(typeError.Field, domain.ViolationType, typeError.Field, typeError.Type.String(), typeError.Value)
+	}
+	return domain.NewViolation("", domain.ViolationSyntax, err.Error())
+}
@@ -1,15 +1,89 @@
 package controller
 
 import (
+	"context"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
 	"github.com/danilovalente/project-api/domain"
 	"github.com/labstack/echo/v4"
 )
 
+//draining is set once the application starts shutting down
+var draining int32
+
+//Drain makes the readiness probe report DOWN, so no new traffic is routed to the application while it shuts down
+func Drain() {
+	atomic.StoreInt32(&draining, 1)
+}
+
 //CheckHealth handles the application Health Check
 func CheckHealth(c echo.Context) error {
+	return CheckLiveness(c)
+}
+
+//CheckLiveness reports the application process is running. Dependencies are not checked, avoiding restarts caused by their failures
+func CheckLiveness(c echo.Context) error {
 	health := domain.Health{}
-	health.Status = "UP"
+	health.Status = domain.HealthStatusUp
+	return c.JSON(http.StatusOK, health)
+}
+
+//CheckReadiness reports if the application can serve requests, running every registered appcontext.HealthChecker
+func CheckReadiness(c echo.Context) error {
+	if atomic.LoadInt32(&draining) == 1 {
+		return c.JSON(http.StatusServiceUnavailable, domain.Health{Status: domain.HealthStatusDown})
+	}
+	health := checkComponents(c.Request().Context(), appcontext.Current.HealthCheckers())
+	if health.Status != domain.HealthStatusUp {
+		return c.JSON(http.StatusServiceUnavailable, health)
+	}
 	return c.JSON(http.StatusOK, health)
 }
+
+//checkComponents runs the HealthCheckers concurrently, each one limited by the HealthCheckTimeout
+func checkComponents(ctx context.Context, healthCheckers map[string]appcontext.HealthChecker) domain.Health {
+	health := domain.Health{Status: domain.HealthStatusUp, Components: make(map[string]domain.ComponentHealth)}
+	var mutex sync.Mutex
+	var waitGroup sync.WaitGroup
+	for componentName, healthChecker := range healthCheckers {
+		waitGroup.Add(1)
+		go func(componentName string, healthChecker appcontext.HealthChecker) {
+			defer waitGroup.Done()
+			componentHealth := checkComponent(ctx, healthChecker)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			health.Components[componentName] = componentHealth
+			if componentHealth.Status != domain.HealthStatusUp {
+				health.Status = domain.HealthStatusDown
+			}
+		}(componentName, healthChecker)
+	}
+	waitGroup.Wait()
+	return health
+}
+
+func checkComponent(ctx context.Context, healthChecker appcontext.HealthChecker) domain.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, config.Values.HealthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := healthChecker.CheckHealth(ctx)
+	componentHealth := domain.ComponentHealth{
+		Status:        domain.HealthStatusUp,
+		LatencyMillis: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		componentHealth.Status = domain.HealthStatusDown
+		componentHealth.Error = err.Error()
+	}
+	if stateReporter, ok := healthChecker.(appcontext.StateReporter); ok {
+		componentHealth.State = stateReporter.State()
+	}
+	return componentHealth
+}
@@ -1,10 +1,15 @@
 package controller
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/domain"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
@@ -21,4 +26,78 @@ func TestGetHealth(t *testing.T) {
 	if assert.NoError(t, CheckHealth(c)) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 	}
-}
\ No newline at end of file
+}
+
+type fakeHealthChecker struct {
+	err error
+}
+
+func (checker fakeHealthChecker) CheckHealth(ctx context.Context) error {
+	return checker.err
+}
+
+func TestGetLiveness(t *testing.T) {
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/project-api/v1/health/live")
+
+	// Assertions
+	if assert.NoError(t, CheckLiveness(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
+
+func TestGetReadiness(t *testing.T) {
+	tests := []struct {
+		name           string
+		healthChecker  fakeHealthChecker
+		expectedCode   int
+		expectedStatus string
+	}{
+		{name: "Ready", healthChecker: fakeHealthChecker{}, expectedCode: http.StatusOK, expectedStatus: domain.HealthStatusUp},
+		{name: "Not Ready", healthChecker: fakeHealthChecker{err: errors.New("connection refused")}, expectedCode: http.StatusServiceUnavailable, expectedStatus: domain.HealthStatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			healthChecker := tt.healthChecker
+			appcontext.Current.Add("FakeHealthChecker", func() appcontext.Component { return healthChecker })
+			appcontext.Current.Get("FakeHealthChecker")
+			defer appcontext.Current.Delete("FakeHealthChecker")
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/health/ready")
+
+			// Assertions
+			if assert.NoError(t, CheckReadiness(c)) {
+				assert.Equal(t, tt.expectedCode, rec.Code)
+				health := domain.Health{}
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+				assert.Equal(t, tt.expectedStatus, health.Status)
+				assert.Equal(t, tt.expectedStatus, health.Components["FakeHealthChecker"].Status)
+			}
+		})
+	}
+}
+
+//fakeStateHealthChecker reports a state, like a circuit breaker
+type fakeStateHealthChecker struct {
+	fakeHealthChecker
+	state string
+}
+
+func (checker fakeStateHealthChecker) State() string {
+	return checker.state
+}
+
+func TestCheckComponentReportsState(t *testing.T) {
+	componentHealth := checkComponent(context.Background(), fakeStateHealthChecker{state: "open", fakeHealthChecker: fakeHealthChecker{err: errors.New("open")}})
+
+	assert.Equal(t, domain.HealthStatusDown, componentHealth.Status)
+	assert.Equal(t, "open", componentHealth.State)
+}
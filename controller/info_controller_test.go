@@ -21,4 +21,4 @@ func TestGetInfo(t *testing.T) {
 	if assert.NoError(t, GetInfo(c)) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danilovalente/project-api/domain"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	//HeaderAcceptLanguage lists the languages preferred by the client
+	HeaderAcceptLanguage = "Accept-Language"
+	//HeaderContentLanguage reports the language of the response messages
+	HeaderContentLanguage = "Content-Language"
+)
+
+//acceptedLanguage is a language range of the Accept-Language header with its quality
+type acceptedLanguage struct {
+	language string
+	quality  float64
+}
+
+//requestLocale negotiates the Locale of the response from the Accept-Language header, falling back to English
+func requestLocale(c echo.Context) *domain.Locale {
+	locale := negotiateLocale(c.Request().Header.Get(HeaderAcceptLanguage))
+	c.Response().Header().Set(HeaderContentLanguage, locale.Language)
+	return locale
+}
+
+//negotiateLocale picks the supported Locale with the highest quality (e.g. pt-BR,pt;q=0.9,en;q=0.8)
+func negotiateLocale(acceptLanguage string) *domain.Locale {
+	acceptedLanguages := make([]acceptedLanguage, 0)
+	for _, languageRange := range strings.Split(acceptLanguage, ",") {
+		parts := strings.Split(languageRange, ";")
+		accepted := acceptedLanguage{language: strings.TrimSpace(parts[0]), quality: 1}
+		for _, parameter := range parts[1:] {
+			parameter = strings.TrimSpace(parameter)
+			if strings.HasPrefix(parameter, "q=") {
+				quality, err := strconv.ParseFloat(strings.TrimPrefix(parameter, "q="), 64)
+				if err != nil {
+					quality = 0
+				}
+				accepted.quality = quality
+			}
+		}
+		if accepted.language != "" && accepted.quality > 0 {
+			acceptedLanguages = append(acceptedLanguages, accepted)
+		}
+	}
+	sort.SliceStable(acceptedLanguages, func(i, j int) bool {
+		return acceptedLanguages[i].quality > acceptedLanguages[j].quality
+	})
+	for _, accepted := range acceptedLanguages {
+		if locale := domain.GetLocale(accepted.language); locale != nil {
+			return locale
+		}
+	}
+	return domain.English
+}
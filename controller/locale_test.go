@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danilovalente/project-api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expectedLocale *domain.Locale
+	}{
+		{name: "Missing", acceptLanguage: "", expectedLocale: domain.English},
+		{name: "Region", acceptLanguage: "pt-BR", expectedLocale: domain.Portuguese},
+		{name: "Quality", acceptLanguage: "en;q=0.5, es;q=0.8, pt;q=0.1", expectedLocale: domain.Spanish},
+		{name: "Unsupported First", acceptLanguage: "fr-FR,fr;q=0.9,pt;q=0.8", expectedLocale: domain.Portuguese},
+		{name: "Refused", acceptLanguage: "pt;q=0, fr", expectedLocale: domain.English},
+		{name: "Wildcard", acceptLanguage: "*", expectedLocale: domain.English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedLocale, negotiateLocale(tt.acceptLanguage))
+		})
+	}
+}
+
+func TestCreateProjectLocalizedViolations(t *testing.T) {
+	tests := []struct {
+		acceptLanguage  string
+		expectedTitle   string
+		expectedDetail  string
+		expectedMessage string
+	}{
+		{acceptLanguage: "en", expectedTitle: "Bad Request", expectedDetail: "The Project is invalid. The required attribute 'name' is missing", expectedMessage: "The required attribute 'name' is missing"},
+		{acceptLanguage: "pt-BR", expectedTitle: "Requisição Inválida", expectedDetail: "O Projeto é inválido. O atributo obrigatório 'name' não foi informado", expectedMessage: "O atributo obrigatório 'name' não foi informado"},
+		{acceptLanguage: "es-ES", expectedTitle: "Solicitud Incorrecta", expectedDetail: "El Proyecto no es válido. Falta el atributo obligatorio 'name'", expectedMessage: "Falta el atributo obligatorio 'name'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			// Setup
+			useMemoryProjectRepository(t)
+			e := echo.New()
+			body := `{"name":"","unitPrice":{"amount":10000,"currency":"EUR"},"timeUnit":"Hour"}`
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(HeaderAcceptLanguage, tt.acceptLanguage)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project")
+
+			// Assertions
+			handle(c, CreateProject)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			problem := domain.Problem{}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			assert.Equal(t, tt.expectedTitle, problem.Title)
+			assert.Equal(t, tt.expectedDetail, problem.Detail)
+			if assert.Len(t, problem.Violations, 1) {
+				assert.Equal(t, tt.expectedMessage, problem.Violations[0].Message)
+			}
+		})
+	}
+}
+
+func TestGetProjectLocalizedDisplay(t *testing.T) {
+	tests := []struct {
+		acceptLanguage  string
+		expectedDisplay string
+	}{
+		{acceptLanguage: "", expectedDisplay: "€1,234,567.89"},
+		{acceptLanguage: "pt-BR", expectedDisplay: "€ 1.234.567,89"},
+		{acceptLanguage: "es", expectedDisplay: "1.234.567,89 €"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			// Setup
+			project := newProject()
+			project.UnitPrice = domain.Money{Amount: 123456789, Currency: "EUR"}
+			useMemoryProjectRepository(t, project)
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(HeaderAcceptLanguage, tt.acceptLanguage)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId")
+			c.SetParamNames("projectId")
+			c.SetParamValues(project.ID.Hex())
+
+			// Assertions
+			handle(c, GetProject)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			response := struct {
+				UnitPrice struct {
+					Display string `json:"display"`
+				} `json:"unitPrice"`
+			}{}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedDisplay, response.UnitPrice.Display)
+		})
+	}
+}
+
+func TestGetProjectLocalizedInvalidID(t *testing.T) {
+	tests := []struct {
+		acceptLanguage  string
+		expectedMessage string
+	}{
+		{acceptLanguage: "en", expectedMessage: "The 'projectId' has an invalid format: invalid"},
+		{acceptLanguage: "pt-BR", expectedMessage: "O 'projectId' tem um formato inválido: invalid"},
+		{acceptLanguage: "es", expectedMessage: "El 'projectId' tiene un formato no válido: invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			// Setup
+			useMemoryProjectRepository(t)
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(HeaderAcceptLanguage, tt.acceptLanguage)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId")
+			c.SetParamNames("projectId")
+			c.SetParamValues("invalid")
+
+			// Assertions
+			handle(c, GetProject)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			problem := domain.Problem{}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			assert.Equal(t, tt.expectedMessage, problem.Detail)
+			if assert.Len(t, problem.Violations, 1) {
+				assert.Equal(t, "projectId", problem.Violations[0].Field)
+				assert.Equal(t, domain.ViolationFormat, problem.Violations[0].Code)
+				assert.Equal(t, tt.expectedMessage, problem.Violations[0].Message)
+			}
+		})
+	}
+}
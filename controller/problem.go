@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"github.com/labstack/echo/v4"
+)
+
+//MIMEApplicationProblemJSON is the media type of the Problem Details (RFC 7807)
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+//HTTPErrorHandler writes the errors returned by the handlers, the Echo errors (e.g. unknown routes) and the recovered panics as Problem Details
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	logger := config.GetContextLogger(c.Request().Context())
+	defer logger.Sync()
+
+	problem := newProblem(c, err)
+	if problem.Status >= http.StatusInternalServerError {
+		logger.Errorf("The request failed with the status %d: %s", problem.Status, err.Error())
+	}
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(problem.Status)
+	} else {
+		c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+		err = c.JSON(problem.Status, problem)
+	}
+	if err != nil {
+		logger.Errorf("An error occurred while trying to write the Problem: %s", err.Error())
+	}
+}
+
+//newProblem describes the error. The details of the unknown errors (e.g. panics) are not exposed
+func newProblem(c echo.Context, err error) domain.Problem {
+	locale := requestLocale(c)
+	problem := domain.Problem{
+		Instance:  c.Request().URL.Path,
+		RequestID: appcontext.GetRequestID(c.Request().Context()),
+	}
+	var httpError *echo.HTTPError
+	var typedError domain.TypedError
+	switch {
+	case errors.As(err, &httpError):
+		if internalError, ok := httpError.Internal.(*echo.HTTPError); ok {
+			httpError = internalError
+		}
+		problem.Type = domain.ProblemTypeBlank
+		problem.Status = httpError.Code
+		problem.Detail = fmt.Sprint(httpError.Message)
+	case errors.As(err, &typedError):
+		problem.Type = typedError.GetType()
+		problem.Status = typedError.GetCode()
+		problem.Title = locale.Message(problem.Type)
+		problem.Detail = typedError.Error()
+	default:
+		problem.Type = domain.ProblemTypeInternalError
+		problem.Status = http.StatusInternalServerError
+		problem.Title = locale.Message(problem.Type)
+		problem.Detail = locale.Message("error.unexpected")
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+
+	var alreadyExists domain.AlreadyExistsError
+	if errors.As(err, &alreadyExists) {
+		problem.Field = alreadyExists.Field
+	}
+	var constraintViolation domain.ConstraintViolationError
+	if errors.As(err, &constraintViolation) {
+		constraintViolation = constraintViolation.Localize(locale)
+		problem.Detail = constraintViolation.Message
+		problem.Violations = constraintViolation.Violations
+	}
+	return problem
+}
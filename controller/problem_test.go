@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danilovalente/project-api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		handler         echo.HandlerFunc
+		path            string
+		expectedStatus  int
+		expectedType    string
+		expectedDetail  string
+		expectedField   string
+		violationsCount int
+	}{
+		{
+			name:           "Not Found",
+			handler:        func(c echo.Context) error { return domain.NotFound("Could not find Project") },
+			expectedStatus: http.StatusNotFound,
+			expectedType:   domain.ProblemTypeNotFound,
+			expectedDetail: "Could not find Project",
+		},
+		{
+			name: "Constraint Violation",
+			handler: func(c echo.Context) error {
+				return domain.ConstraintViolations("project.invalid", domain.NewViolation("name", domain.ViolationRequired, "name"))
+			},
+			expectedStatus:  http.StatusBadRequest,
+			expectedType:    domain.ProblemTypeConstraintViolation,
+			expectedDetail:  "The Project is invalid. The required attribute 'name' is missing",
+			violationsCount: 1,
+		},
+		{
+			name:           "Already Exists",
+			handler:        func(c echo.Context) error { return domain.AlreadyExistsForField("name", "Duplicated") },
+			expectedStatus: http.StatusConflict,
+			expectedType:   domain.ProblemTypeAlreadyExists,
+			expectedDetail: "Duplicated",
+			expectedField:  "name",
+		},
+		{
+			name:           "Service Unavailable",
+			handler:        func(c echo.Context) error { return domain.ServiceUnavailable("The database is unavailable") },
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedType:   domain.ProblemTypeServiceUnavailable,
+			expectedDetail: "The database is unavailable",
+		},
+		{
+			name:           "Echo Error",
+			handler:        func(c echo.Context) error { return echo.ErrMethodNotAllowed },
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedType:   domain.ProblemTypeBlank,
+			expectedDetail: "Method Not Allowed",
+		},
+		{
+			name:           "Unknown Route",
+			path:           "/unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedType:   domain.ProblemTypeBlank,
+			expectedDetail: "Not Found",
+		},
+		{
+			name:           "Unknown Error",
+			handler:        func(c echo.Context) error { return errors.New("secret connection string") },
+			expectedStatus: http.StatusInternalServerError,
+			expectedType:   domain.ProblemTypeInternalError,
+			expectedDetail: "An unexpected error occurred",
+		},
+		{
+			name:           "Panic",
+			handler:        func(c echo.Context) error { panic("secret connection string") },
+			expectedStatus: http.StatusInternalServerError,
+			expectedType:   domain.ProblemTypeInternalError,
+			expectedDetail: "An unexpected error occurred",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			e := echo.New()
+			e.HTTPErrorHandler = HTTPErrorHandler
+			e.Use(RequestID)
+			e.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{DisablePrintStack: true}))
+			e.GET("/project", tt.handler)
+			path := tt.path
+			if path == "" {
+				path = "/project"
+			}
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set(echo.HeaderXRequestID, "client-request-id")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			// Assertions
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+			problem := domain.Problem{}
+			if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem)) {
+				assert.Equal(t, tt.expectedType, problem.Type)
+				assert.Equal(t, http.StatusText(tt.expectedStatus), problem.Title)
+				assert.Equal(t, tt.expectedStatus, problem.Status)
+				assert.Equal(t, tt.expectedDetail, problem.Detail)
+				assert.Equal(t, path, problem.Instance)
+				assert.Equal(t, "client-request-id", problem.RequestID)
+				assert.Equal(t, tt.expectedField, problem.Field)
+				assert.Len(t, problem.Violations, tt.violationsCount)
+			}
+		})
+	}
+}
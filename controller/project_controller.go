@@ -1,14 +1,14 @@
 package controller
 
 import (
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/labstack/echo/v4"
 	"github.com/danilovalente/project-api/config"
 	"github.com/danilovalente/project-api/domain"
+	"github.com/labstack/echo/v4"
 )
 
 const (
@@ -17,114 +17,332 @@ const (
 
 //CreateProject creates a new Project
 func CreateProject(c echo.Context) error {
-	logger := config.GetLogger
-	defer logger().Sync()
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
 
 	project := new(domain.Project)
-	if err := c.Bind(project); err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ConstraintViolation("An error occurred while trying to read the request body: "+err.Error()))
+	if err := bind(c, project); err != nil {
+		return err
 	}
 
-	project, err := domain.GetProjectCreateUsecase().Execute(project)
+	project, err := domain.GetProjectCreateUsecase().Execute(ctx, project)
 
 	if err != nil {
-		logger().Errorf("An error occurred while trying to Create the Project: %s", err.Error())
-		return c.JSON(err.(domain.IdentifiableError).GetCode(), err)
+		logger.Errorf("An error occurred while trying to Create the Project: %s", err.Error())
+		return err
 	}
 
-	return c.JSON(http.StatusCreated, project)
+	return c.JSON(http.StatusCreated, project.Localized(requestLocale(c)))
 }
 
 //GetProjectList of the collection
 func GetProjectList(c echo.Context) error {
-	logger := config.GetLogger
-	defer logger().Sync()
-	var lastProjectID = c.QueryParam("lastProjectId")
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+	lastProjectID, pageSize, err := pageParams(c, "lastProjectId")
+	if err != nil {
+		return err
+	}
+
+	projectList, err := domain.GetProjectGetAllUsecase().Execute(ctx, lastProjectID, pageSize)
+	if err != nil {
+		logger.Errorf("An error occurred while trying to Get the Project List: %s", err.Error())
+		return err
+	}
+
+	return c.JSON(http.StatusOK, localizedProjects(c, projectList))
+}
+
+//GetProjectTrash lists the deleted Projects, which may still be restored
+func GetProjectTrash(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+	lastProjectID, pageSize, err := pageParams(c, "lastProjectId")
+	if err != nil {
+		return err
+	}
+
+	projectList, err := domain.GetProjectGetTrashUsecase().Execute(ctx, lastProjectID, pageSize)
+	if err != nil {
+		logger.Errorf("An error occurred while trying to Get the deleted Project List: %s", err.Error())
+		return err
+	}
+
+	return c.JSON(http.StatusOK, localizedProjects(c, projectList))
+}
+
+//GetProjectHistory lists the changes of the Project provided the projectId, oldest first
+func GetProjectHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+	projectID := strings.TrimSpace(c.Param("projectId"))
+	lastEntryID, pageSize, err := pageParams(c, "lastEntryId")
+	if err != nil {
+		return err
+	}
+
+	entries, err := domain.GetProjectHistoryUsecase().Execute(ctx, projectID, lastEntryID, pageSize)
+	if err != nil {
+		logger.Errorf("An error occurred while trying to Get the history of the Project with ID %s: %s", projectID, err.Error())
+		return err
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+//pageParams reads the ID of the last item of the previous page, from the lastIDParam, and the pageSize (DefaultProjectPageSize if missing) of a list
+func pageParams(c echo.Context, lastIDParam string) (string, int64, error) {
+	var lastProjectID = c.QueryParam(lastIDParam)
 	var pageSizeString = c.QueryParam("pageSize")
 
-	var pageSize int
 	if pageSizeString == "" {
-		pageSize = DefaultProjectPageSize
-	} else {
-		var err error
-		pageSize, err = strconv.Atoi(pageSizeString)
-		if err != nil {
-			msg := fmt.Sprintf("Invalid format for pageSize %s. Message: %s", pageSizeString, err.Error())
-			logger().Error(msg)
-			return c.JSON(http.StatusBadRequest, domain.ConstraintViolation(msg))
-		}
+		return lastProjectID, DefaultProjectPageSize, nil
 	}
-
-	projectList, err := domain.GetProjectGetAllUsecase().Execute(lastProjectID, int64(pageSize))
+	pageSize, err := strconv.Atoi(pageSizeString)
 	if err != nil {
-		logger().Errorf("An error occurred while trying to Get the Project List: %s", err.Error())
-		return c.JSON(err.(domain.IdentifiableError).GetCode(), err)
+		config.GetContextLogger(c.Request().Context()).Errorf("Invalid format for pageSize %s. Message: %s", pageSizeString, err.Error())
+		return "", 0, domain.FieldViolation("pageSize", domain.ViolationFormat, "pageSize", pageSizeString)
 	}
+	return lastProjectID, int64(pageSize), nil
+}
 
-	return c.JSON(http.StatusOK, projectList)
+//localizedProjects copies the Projects of a list, localized to the request
+func localizedProjects(c echo.Context, projectList []*domain.Project) []domain.Project {
+	locale := requestLocale(c)
+	localizedProjectList := make([]domain.Project, len(projectList))
+	for index, project := range projectList {
+		localizedProjectList[index] = project.Localized(locale)
+	}
+	return localizedProjectList
 }
 
 //GetProject provided the projectId
 func GetProject(c echo.Context) error {
-	logger := config.GetLogger
-	defer logger().Sync()
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
 	projectID := strings.TrimSpace(c.Param("projectId"))
 
 	if projectID == "" {
-		return c.JSON(http.StatusBadRequest, domain.ConstraintViolation("Bad request. Missing mandatory request value projectId"))
+		return domain.FieldViolation("projectId", domain.ViolationRequired, "projectId")
+	}
+
+	var project *domain.Project
+	var err error
+	if asOf := strings.TrimSpace(c.QueryParam("asOf")); asOf != "" {
+		date, parseErr := time.Parse(time.RFC3339, asOf)
+		if parseErr != nil {
+			return domain.FieldViolation("asOf", domain.ViolationFormat, "asOf", asOf)
+		}
+		project, err = domain.GetProjectGetAsOfUsecase().Execute(ctx, projectID, date)
+	} else {
+		project, err = domain.GetProjectGetByIDUsecase().Execute(ctx, projectID)
+	}
+	if err != nil {
+		logger.Errorf("An error occurred while trying to Get the Project: %s", err.Error())
+		return err
+	}
+
+	return c.JSON(http.StatusOK, project.Localized(requestLocale(c)))
+}
+
+//GetProjectVersion gets a numbered version of the Project provided the projectId
+func GetProjectVersion(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+	projectID := strings.TrimSpace(c.Param("projectId"))
+	number, err := versionParam("version", c.Param("version"))
+	if err != nil {
+		return err
 	}
 
-	project, err := domain.GetProjectGetByIDUsecase().Execute(projectID)
+	version, err := domain.GetProjectGetVersionUsecase().Execute(ctx, projectID, number)
 	if err != nil {
-		logger().Errorf("An error occurred while trying to Get the Project: %s", err.Error())
-		return c.JSON(err.(domain.IdentifiableError).GetCode(), err)
+		logger.Errorf("An error occurred while trying to Get the version %d of the Project with ID %s: %s", number, projectID, err.Error())
+		return err
 	}
 
-	return c.JSON(http.StatusOK, project)
+	return c.JSON(http.StatusOK, version.Localized(requestLocale(c)))
+}
+
+//DiffProject lists the fields of the Project provided the projectId changed between the versions from and to
+func DiffProject(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+	projectID := strings.TrimSpace(c.Param("projectId"))
+	from, err := versionParam("from", c.QueryParam("from"))
+	if err != nil {
+		return err
+	}
+	to, err := versionParam("to", c.QueryParam("to"))
+	if err != nil {
+		return err
+	}
+
+	diff, err := domain.GetProjectDiffUsecase().Execute(ctx, projectID, from, to)
+	if err != nil {
+		logger.Errorf("An error occurred while trying to compare the versions %d and %d of the Project with ID %s: %s", from, to, projectID, err.Error())
+		return err
+	}
+
+	return c.JSON(http.StatusOK, diff)
+}
+
+//RevertProject updates the Project provided the projectId back to one of its versions
+func RevertProject(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+	projectID := strings.TrimSpace(c.Param("projectId"))
+	number, err := versionParam("version", c.Param("version"))
+	if err != nil {
+		return err
+	}
+
+	project, err := domain.GetProjectRevertUsecase().Execute(ctx, projectID, number)
+	if err != nil {
+		logger.Errorf("An error occurred while trying to revert the Project with ID %s to the version %d: %s", projectID, number, err.Error())
+		return err
+	}
+
+	return c.JSON(http.StatusOK, project.Localized(requestLocale(c)))
+}
+
+//versionParam reads the required version number of the param
+func versionParam(name string, value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, domain.FieldViolation(name, domain.ViolationRequired, name)
+	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, domain.FieldViolation(name, domain.ViolationFormat, name, value)
+	}
+	if number < 1 {
+		return 0, domain.FieldViolation(name, domain.ViolationPositive, name)
+	}
+	return number, nil
+}
+
+//PriceProject itemizes the price of the quantity, in the TimeUnit of the Project, per pricing tier
+func PriceProject(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+	projectID := strings.TrimSpace(c.Param("projectId"))
+	quantity := strings.TrimSpace(c.QueryParam("quantity"))
+
+	if projectID == "" {
+		return domain.FieldViolation("projectId", domain.ViolationRequired, "projectId")
+	}
+	if quantity == "" {
+		return domain.FieldViolation("quantity", domain.ViolationRequired, "quantity")
+	}
+
+	breakdown, err := domain.GetProjectPriceUsecase().Execute(ctx, projectID, quantity)
+	if err != nil {
+		logger.Errorf("An error occurred while trying to Price the Project: %s", err.Error())
+		return err
+	}
+
+	return c.JSON(http.StatusOK, breakdown.Localized(requestLocale(c)))
+}
+
+//QuoteProject prices a quantity of work in any time unit, converted to the TimeUnit of the Project
+func QuoteProject(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+	projectID := strings.TrimSpace(c.Param("projectId"))
+
+	if projectID == "" {
+		return domain.FieldViolation("projectId", domain.ViolationRequired, "projectId")
+	}
+
+	request := domain.QuoteRequest{}
+	if err := bind(c, &request); err != nil {
+		return err
+	}
+
+	quote, err := domain.GetProjectQuoteUsecase().Execute(ctx, projectID, request)
+	if err != nil {
+		logger.Errorf("An error occurred while trying to Quote the Project: %s", err.Error())
+		return err
+	}
+
+	return c.JSON(http.StatusOK, quote.Localized(requestLocale(c)))
 }
 
 //UpdateProject updates the Project
 func UpdateProject(c echo.Context) error {
-	logger := config.GetLogger
-	defer logger().Sync()
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
 	projectID := strings.TrimSpace(c.Param("projectId"))
 	if projectID == "" {
-		return c.JSON(http.StatusBadRequest, domain.ConstraintViolation("Bad request. Missing mandatory request value projectId"))
+		return domain.FieldViolation("projectId", domain.ViolationRequired, "projectId")
 	}
 
 	project := domain.Project{}
-	if err := c.Bind(&project); err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ConstraintViolation("An error occurred while trying to read the request body: "+err.Error()))
+	if err := bind(c, &project); err != nil {
+		return err
 	}
 
 	if projectID != project.ID.Hex() {
-		return c.JSON(http.StatusBadRequest, domain.ConstraintViolation("The content of the URL Path Parameter projectId is different of the Body's id"))
+		return domain.FieldViolation("id", domain.ViolationMismatch, "id", "projectId")
 	}
 
-	err := domain.GetProjectUpdateUsecase().Execute(&project)
+	err := domain.GetProjectUpdateUsecase().Execute(ctx, &project)
 	if err != nil {
-		logger().Errorf("An error occurred while trying to Update the Project: %s", err.Error())
-		return c.JSON(err.(domain.IdentifiableError).GetCode(), err)
+		logger.Errorf("An error occurred while trying to Update the Project: %s", err.Error())
+		return err
 	}
 
 	return c.JSON(http.StatusOK, "")
 
 }
 
-//DeleteProject provided the projectId
+//RestoreProject takes the deleted Project back from the trash
+func RestoreProject(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+	projectID := strings.TrimSpace(c.Param("projectId"))
+
+	if projectID == "" {
+		return domain.FieldViolation("projectId", domain.ViolationRequired, "projectId")
+	}
+
+	project, err := domain.GetProjectRestoreUsecase().Execute(ctx, projectID)
+	if err != nil {
+		logger.Errorf("An error occurred while trying to Restore the Project: %s", err.Error())
+		return err
+	}
+
+	return c.JSON(http.StatusOK, project.Localized(requestLocale(c)))
+}
+
+//DeleteProject moves the Project with the projectId to the trash
 func DeleteProject(c echo.Context) error {
-	logger := config.GetLogger
-	defer logger().Sync()
+	ctx := c.Request().Context()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
 	projectID := strings.TrimSpace(c.Param("projectId"))
 
 	if projectID == "" {
-		return c.JSON(http.StatusBadRequest, domain.ConstraintViolation("Bad request. Missing mandatory request value projectId"))
+		return domain.FieldViolation("projectId", domain.ViolationRequired, "projectId")
 	}
 
-	err := domain.GetProjectDeleteUsecase().Execute(projectID)
+	err := domain.GetProjectDeleteUsecase().Execute(ctx, projectID)
 	if err != nil {
-		logger().Errorf("An error occurred while trying to Delete the Project: %s", err.Error())
-		return c.JSON(err.(domain.IdentifiableError).GetCode(), err)
+		logger.Errorf("An error occurred while trying to Delete the Project: %s", err.Error())
+		return err
 	}
 
 	return c.JSON(http.StatusOK, "")
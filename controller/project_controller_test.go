@@ -0,0 +1,738 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	_ "github.com/danilovalente/project-api/gateway/customlog"
+	"github.com/danilovalente/project-api/gateway/memory"
+	_ "github.com/danilovalente/project-api/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func init() {
+	config.UseProfile(appcontext.ProfileTest)
+}
+
+//useMemoryProjectRepository wires the real usecases to an in-memory repository holding the provided projects
+func useMemoryProjectRepository(t *testing.T, projects ...domain.Project) *memory.ProjectRepository {
+	repo := memory.NewProjectRepository()
+	if err := repo.Load(projects...); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(appcontext.Current.Override(appcontext.ProjectRepository, repo))
+	t.Cleanup(appcontext.Current.Override(appcontext.AuditRepository, repo.Audit()))
+	t.Cleanup(appcontext.Current.Override(appcontext.ProjectVersionRepository, repo.Versions()))
+	return repo
+}
+
+//storedProjects lists all the projects kept by the repository
+//handle runs the handler as the Echo router does, writing the returned error as a Problem with the HTTPErrorHandler
+func handle(c echo.Context, h echo.HandlerFunc) {
+	if err := h(c); err != nil {
+		HTTPErrorHandler(err, c)
+	}
+}
+
+func storedProjects(t *testing.T, repo *memory.ProjectRepository) []*domain.Project {
+	projects, err := repo.GetAll(context.Background(), "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return projects
+}
+
+func newProject() domain.Project {
+	projectID := primitive.NewObjectID()
+	return domain.Project{
+		ID:        projectID,
+		Name:      "Project " + projectID.Hex(),
+		UnitPrice: domain.Money{Amount: 10000, Currency: "EUR"},
+		TimeUnit:  "Hour",
+	}
+}
+
+func TestCreateProject(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{name: "Created", body: `{"name":"Project API","unitPrice":{"amount":10000,"currency":"EUR"},"timeUnit":"Hour"}`, expectedCode: http.StatusCreated},
+		{name: "Invalid Project", body: `{"name":"","unitPrice":{"amount":10000,"currency":"EUR"},"timeUnit":"Hour"}`, expectedCode: http.StatusBadRequest},
+		{name: "Invalid Body", body: `{"name":`, expectedCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			repo := useMemoryProjectRepository(t)
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project")
+
+			// Assertions
+			handle(c, CreateProject)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusCreated {
+				assert.Len(t, storedProjects(t, repo), 1)
+			} else {
+				assert.Empty(t, storedProjects(t, repo))
+			}
+		})
+	}
+}
+
+func TestCreateProjectWithDuplicateName(t *testing.T) {
+	// Setup
+	project := newProject()
+	useMemoryProjectRepository(t, project)
+	body := `{"name":"` + strings.ToUpper(project.Name) + `","unitPrice":{"amount":10000,"currency":"EUR"},"timeUnit":"Hour"}`
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/project-api/v1/project")
+
+	// Assertions
+	handle(c, CreateProject)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	response := domain.AlreadyExistsError{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "name", response.Field)
+}
+
+func TestCreateProjectViolations(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		expectedViolations []domain.Violation
+	}{
+		{
+			name: "Every invalid field",
+			body: `{"name":" ","unitPrice":{"amount":0,"currency":""},"timeUnit":"Year"}`,
+			expectedViolations: []domain.Violation{
+				{Field: "name", Code: domain.ViolationRequired, Message: "The required attribute 'name' is missing"},
+				{Field: "unitPrice.currency", Code: domain.ViolationRequired, Message: "The required attribute 'unitPrice.currency' is missing"},
+				{Field: "unitPrice.amount", Code: domain.ViolationPositive, Message: "The 'unitPrice.amount' must be greater than zero"},
+				{Field: "timeUnit", Code: domain.ViolationOneOf, Message: "The 'timeUnit' must be any of [Hour, Day, Week, Month]"},
+			},
+		},
+		{
+			name: "Wrong type",
+			body: `{"name":"Project API","unitPrice":{"amount":10000,"currency":"EUR"},"timeUnit":1}`,
+			expectedViolations: []domain.Violation{
+				{Field: "timeUnit", Code: domain.ViolationType, Message: "The 'timeUnit' must be a string, not a number"},
+			},
+		},
+		{
+			name: "Wrong amount type",
+			body: `{"name":"Project API","unitPrice":{"amount":true,"currency":"EUR"},"timeUnit":"Hour"}`,
+			expectedViolations: []domain.Violation{
+				{Field: "unitPrice.amount", Code: domain.ViolationType, Message: "The 'unitPrice.amount' must be a number, not a bool"},
+			},
+		},
+		{
+			name: "Unknown currency",
+			body: `{"name":"Project API","unitPrice":{"amount":"100.00","currency":"xyz"},"timeUnit":"Hour"}`,
+			expectedViolations: []domain.Violation{
+				{Field: "unitPrice.currency", Code: domain.ViolationCurrency, Message: "The 'unitPrice.currency' must be an ISO 4217 currency code, not 'XYZ'"},
+			},
+		},
+		{
+			name: "Precision",
+			body: `{"name":"Project API","unitPrice":{"amount":"100.5","currency":"JPY"},"timeUnit":"Hour"}`,
+			expectedViolations: []domain.Violation{
+				{Field: "unitPrice.amount", Code: domain.ViolationPrecision, Message: "The 'unitPrice.amount' must have at most 0 decimal places"},
+			},
+		},
+		{
+			name: "Decimal format",
+			body: `{"name":"Project API","unitPrice":{"amount":"1,234.50","currency":"USD"},"timeUnit":"Hour"}`,
+			expectedViolations: []domain.Violation{
+				{Field: "unitPrice.amount", Code: domain.ViolationFormat, Message: "The 'unitPrice.amount' has an invalid format: 1,234.50"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			useMemoryProjectRepository(t)
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project")
+
+			// Assertions
+			handle(c, CreateProject)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			response := domain.ConstraintViolationError{}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedViolations, response.Violations)
+		})
+	}
+}
+
+func TestCreateProjectWithDecimalAmount(t *testing.T) {
+	// Setup
+	repo := useMemoryProjectRepository(t)
+	body := `{"name":"Project API","unitPrice":{"amount":"1234.5","currency":"usd"},"timeUnit":"Hour"}`
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/project-api/v1/project")
+
+	// Assertions
+	handle(c, CreateProject)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"unitPrice":{"amount":123450,"currency":"USD","formatted":"1234.50","display":"$1,234.50"}`)
+	if projects := storedProjects(t, repo); assert.Len(t, projects, 1) {
+		assert.Equal(t, domain.Money{Amount: 123450, Currency: "USD"}, projects[0].UnitPrice)
+	}
+}
+
+func TestGetProject(t *testing.T) {
+	project := newProject()
+	tests := []struct {
+		name         string
+		projectID    string
+		expectedCode int
+	}{
+		{name: "Found", projectID: project.ID.Hex(), expectedCode: http.StatusOK},
+		{name: "Not Found", projectID: primitive.NewObjectID().Hex(), expectedCode: http.StatusNotFound},
+		{name: "Invalid ID", projectID: "invalid", expectedCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			useMemoryProjectRepository(t, project)
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId")
+			c.SetParamNames("projectId")
+			c.SetParamValues(tt.projectID)
+
+			// Assertions
+			handle(c, GetProject)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				response := domain.Project{}
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, project.Name, response.Name)
+			}
+		})
+	}
+}
+
+func TestPriceProject(t *testing.T) {
+	project := newProject()
+	project.PricingTiers = []domain.PricingTier{{From: "100", UnitPrice: domain.Money{Amount: 8000, Currency: "EUR"}}}
+	project.Discount = "10"
+	tests := []struct {
+		name          string
+		projectID     string
+		quantity      string
+		expectedCode  int
+		expectedTotal int64
+	}{
+		{name: "Priced", projectID: project.ID.Hex(), quantity: "150", expectedCode: http.StatusOK, expectedTotal: 1260000},
+		{name: "Missing quantity", projectID: project.ID.Hex(), expectedCode: http.StatusBadRequest},
+		{name: "Invalid quantity", projectID: project.ID.Hex(), quantity: "ten", expectedCode: http.StatusBadRequest},
+		{name: "Zero quantity", projectID: project.ID.Hex(), quantity: "0", expectedCode: http.StatusBadRequest},
+		{name: "Not Found", projectID: primitive.NewObjectID().Hex(), quantity: "1", expectedCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			useMemoryProjectRepository(t, project)
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/?quantity="+tt.quantity, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId/price")
+			c.SetParamNames("projectId")
+			c.SetParamValues(tt.projectID)
+
+			// Assertions
+			handle(c, PriceProject)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				response := domain.PriceBreakdown{}
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Len(t, response.Lines, 2)
+				assert.Equal(t, tt.expectedTotal, response.Total.Amount)
+			}
+		})
+	}
+}
+
+func TestQuoteProject(t *testing.T) {
+	project := newProject()
+	tests := []struct {
+		name                      string
+		projectID                 string
+		body                      string
+		expectedCode              int
+		expectedConvertedQuantity string
+		expectedTotal             int64
+	}{
+		{name: "Weeks of an hourly Project", projectID: project.ID.Hex(), body: `{"quantity":3,"unit":"Week"}`, expectedCode: http.StatusOK, expectedConvertedQuantity: "120", expectedTotal: 1200000},
+		{name: "Decimal string quantity", projectID: project.ID.Hex(), body: `{"quantity":"2.5","unit":"Day"}`, expectedCode: http.StatusOK, expectedConvertedQuantity: "20", expectedTotal: 200000},
+		{name: "Same unit", projectID: project.ID.Hex(), body: `{"quantity":1,"unit":"Hour"}`, expectedCode: http.StatusOK, expectedConvertedQuantity: "1", expectedTotal: 10000},
+		{name: "Invalid unit", projectID: project.ID.Hex(), body: `{"quantity":1,"unit":"Year"}`, expectedCode: http.StatusBadRequest},
+		{name: "Missing quantity", projectID: project.ID.Hex(), body: `{"unit":"Day"}`, expectedCode: http.StatusBadRequest},
+		{name: "Negative quantity", projectID: project.ID.Hex(), body: `{"quantity":-1,"unit":"Day"}`, expectedCode: http.StatusBadRequest},
+		{name: "Invalid Body", projectID: project.ID.Hex(), body: `{"quantity":`, expectedCode: http.StatusBadRequest},
+		{name: "Object quantity", projectID: project.ID.Hex(), body: `{"quantity":{},"unit":"Day"}`, expectedCode: http.StatusBadRequest},
+		{name: "Bool quantity", projectID: project.ID.Hex(), body: `{"quantity":true,"unit":"Day"}`, expectedCode: http.StatusBadRequest},
+		{name: "Null quantity", projectID: project.ID.Hex(), body: `{"quantity":null,"unit":"Day"}`, expectedCode: http.StatusBadRequest},
+		{name: "Not Found", projectID: primitive.NewObjectID().Hex(), body: `{"quantity":1,"unit":"Day"}`, expectedCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			useMemoryProjectRepository(t, project)
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId/quote")
+			c.SetParamNames("projectId")
+			c.SetParamValues(tt.projectID)
+
+			// Assertions
+			handle(c, QuoteProject)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				response := domain.Quote{}
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedConvertedQuantity, response.ConvertedQuantity)
+				assert.Equal(t, "Hour", response.TimeUnit)
+				assert.Equal(t, project.UnitPrice.Amount, response.UnitPrice.Amount)
+				assert.Equal(t, tt.expectedTotal, response.Total.Amount)
+			}
+		})
+	}
+}
+
+func TestGetProjectList(t *testing.T) {
+	// Setup
+	useMemoryProjectRepository(t, newProject(), newProject())
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/?pageSize=10", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/project-api/v1/project")
+
+	// Assertions
+	handle(c, GetProjectList)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	response := make([]domain.Project, 0)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response, 2)
+}
+
+func TestUpdateProject(t *testing.T) {
+	project := newProject()
+	tests := []struct {
+		name         string
+		projectID    string
+		expectedCode int
+	}{
+		{name: "Updated", projectID: project.ID.Hex(), expectedCode: http.StatusOK},
+		{name: "Different IDs", projectID: primitive.NewObjectID().Hex(), expectedCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			repo := useMemoryProjectRepository(t, project)
+			body := `{"id":"` + project.ID.Hex() + `","name":"Renamed","unitPrice":{"amount":12000,"currency":"EUR"},"timeUnit":"Day"}`
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId")
+			c.SetParamNames("projectId")
+			c.SetParamValues(tt.projectID)
+
+			// Assertions
+			handle(c, UpdateProject)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				stored, _ := repo.Get(context.Background(), project.ID.Hex())
+				assert.Equal(t, "Renamed", stored.Name)
+			}
+		})
+	}
+}
+
+func TestDeleteProject(t *testing.T) {
+	project := newProject()
+	tests := []struct {
+		name         string
+		projectID    string
+		expectedCode int
+	}{
+		{name: "Deleted", projectID: project.ID.Hex(), expectedCode: http.StatusOK},
+		{name: "Not Found", projectID: primitive.NewObjectID().Hex(), expectedCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			repo := useMemoryProjectRepository(t, project)
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			req.Header.Set(HeaderXUser, "alice")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId")
+			c.SetParamNames("projectId")
+			c.SetParamValues(tt.projectID)
+
+			// Assertions
+			handle(c, Actor(DeleteProject))
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				assert.Empty(t, storedProjects(t, repo))
+				trash, _ := repo.GetTrash(context.Background(), "", 0)
+				if assert.Len(t, trash, 1) {
+					assert.Equal(t, "alice", trash[0].DeletedBy)
+				}
+			}
+		})
+	}
+}
+
+//deletedProject is a Project in the trash
+func deletedProject() domain.Project {
+	project := newProject()
+	project.DeletedAt = time.Now()
+	project.DeletedBy = "alice"
+	return project
+}
+
+func TestGetProjectTrash(t *testing.T) {
+	// Setup
+	deleted := deletedProject()
+	useMemoryProjectRepository(t, newProject(), deleted)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/?pageSize=10", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/project-api/v1/project/trash")
+
+	// Assertions
+	handle(c, GetProjectTrash)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	response := make([]domain.Project, 0)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	if assert.Len(t, response, 1) {
+		assert.Equal(t, deleted.ID, response[0].ID)
+		assert.Equal(t, "alice", response[0].DeletedBy)
+		assert.False(t, response[0].DeletedAt.IsZero())
+	}
+}
+
+func TestRestoreProject(t *testing.T) {
+	deleted := deletedProject()
+	project := newProject()
+	tests := []struct {
+		name         string
+		projectID    string
+		expectedCode int
+	}{
+		{name: "Restored", projectID: deleted.ID.Hex(), expectedCode: http.StatusOK},
+		{name: "Not deleted", projectID: project.ID.Hex(), expectedCode: http.StatusNotFound},
+		{name: "Not Found", projectID: primitive.NewObjectID().Hex(), expectedCode: http.StatusNotFound},
+		{name: "Invalid ID", projectID: "invalid", expectedCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			repo := useMemoryProjectRepository(t, project, deleted)
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId/restore")
+			c.SetParamNames("projectId")
+			c.SetParamValues(tt.projectID)
+
+			// Assertions
+			handle(c, RestoreProject)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				assert.Len(t, storedProjects(t, repo), 2)
+				response := domain.Project{}
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.True(t, response.DeletedAt.IsZero())
+			}
+		})
+	}
+}
+
+func TestGetProjectHistory(t *testing.T) {
+	// Setup
+	repo := useMemoryProjectRepository(t)
+	ctx := appcontext.WithActor(context.Background(), "alice")
+	project := newProject()
+	project.ID = primitive.NilObjectID
+	saved, _ := repo.Save(ctx, &project)
+	saved.Name = "Project API v2"
+	_, _ = repo.Update(ctx, saved)
+	tests := []struct {
+		name            string
+		projectID       string
+		query           string
+		expectedCode    int
+		expectedActions []string
+	}{
+		{name: "History", projectID: saved.ID.Hex(), expectedCode: http.StatusOK, expectedActions: []string{domain.AuditCreated, domain.AuditUpdated}},
+		{name: "Paged", projectID: saved.ID.Hex(), query: "?pageSize=1", expectedCode: http.StatusOK, expectedActions: []string{domain.AuditCreated}},
+		{name: "Unknown Project", projectID: primitive.NewObjectID().Hex(), expectedCode: http.StatusOK, expectedActions: []string{}},
+		{name: "Invalid ID", projectID: "invalid", expectedCode: http.StatusBadRequest},
+		{name: "Invalid page size", projectID: saved.ID.Hex(), query: "?pageSize=x", expectedCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId/history")
+			c.SetParamNames("projectId")
+			c.SetParamValues(tt.projectID)
+
+			// Assertions
+			handle(c, GetProjectHistory)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				response := make([]domain.AuditEntry, 0)
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				actions := make([]string, 0)
+				for _, entry := range response {
+					assert.Equal(t, "alice", entry.Actor)
+					actions = append(actions, entry.Action)
+				}
+				assert.Equal(t, tt.expectedActions, actions)
+			}
+		})
+	}
+}
+
+//versionedProject saves a Project named Project API, then renames it to Project API v2, returning it and the time between the versions
+func versionedProject(t *testing.T, repo *memory.ProjectRepository) (*domain.Project, time.Time) {
+	ctx := appcontext.WithActor(context.Background(), "alice")
+	project := newProject()
+	project.ID = primitive.NilObjectID
+	project.Name = "Project API"
+	saved, err := repo.Save(ctx, &project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	between := time.Now()
+	time.Sleep(2 * time.Millisecond)
+	saved.Name = "Project API v2"
+	if _, err = repo.Update(ctx, saved); err != nil {
+		t.Fatal(err)
+	}
+	return saved, between
+}
+
+func TestGetProjectAsOf(t *testing.T) {
+	// Setup
+	repo := useMemoryProjectRepository(t)
+	project, between := versionedProject(t, repo)
+	tests := []struct {
+		name         string
+		asOf         string
+		expectedCode int
+		expectedName string
+	}{
+		{name: "Before the update", asOf: between.Format(time.RFC3339Nano), expectedCode: http.StatusOK, expectedName: "Project API"},
+		{name: "Now", asOf: time.Now().Add(time.Second).Format(time.RFC3339), expectedCode: http.StatusOK, expectedName: "Project API v2"},
+		{name: "Before the creation", asOf: "2000-01-01T00:00:00Z", expectedCode: http.StatusNotFound},
+		{name: "Invalid date", asOf: "yesterday", expectedCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/?asOf="+url.QueryEscape(tt.asOf), nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId")
+			c.SetParamNames("projectId")
+			c.SetParamValues(project.ID.Hex())
+
+			// Assertions
+			handle(c, GetProject)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				response := domain.Project{}
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedName, response.Name)
+			}
+		})
+	}
+
+	//A Project in the trash at the date is not found
+	assert.NoError(t, repo.Delete(context.Background(), project.ID.Hex(), "alice"))
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/?asOf="+url.QueryEscape(time.Now().Add(time.Second).Format(time.RFC3339)), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("projectId")
+	c.SetParamValues(project.ID.Hex())
+	handle(c, GetProject)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGetProjectVersion(t *testing.T) {
+	// Setup
+	repo := useMemoryProjectRepository(t)
+	project, _ := versionedProject(t, repo)
+	tests := []struct {
+		name         string
+		version      string
+		expectedCode int
+		expectedName string
+	}{
+		{name: "First", version: "1", expectedCode: http.StatusOK, expectedName: "Project API"},
+		{name: "Second", version: "2", expectedCode: http.StatusOK, expectedName: "Project API v2"},
+		{name: "Not Found", version: "3", expectedCode: http.StatusNotFound},
+		{name: "Zero", version: "0", expectedCode: http.StatusBadRequest},
+		{name: "Invalid", version: "x", expectedCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId/versions/:version")
+			c.SetParamNames("projectId", "version")
+			c.SetParamValues(project.ID.Hex(), tt.version)
+
+			// Assertions
+			handle(c, GetProjectVersion)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				response := domain.ProjectVersion{}
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, tt.version, strconv.FormatInt(response.Number, 10))
+				assert.Equal(t, "alice", response.CreatedBy)
+				assert.Equal(t, tt.expectedName, response.Project.Name)
+				assert.Contains(t, rec.Body.String(), `"display":"€100.00"`)
+			}
+		})
+	}
+}
+
+func TestDiffProject(t *testing.T) {
+	// Setup
+	repo := useMemoryProjectRepository(t)
+	project, _ := versionedProject(t, repo)
+	tests := []struct {
+		name            string
+		query           string
+		expectedCode    int
+		expectedChanges []domain.FieldChange
+	}{
+		{name: "Forward", query: "?from=1&to=2", expectedCode: http.StatusOK, expectedChanges: []domain.FieldChange{{Field: "name", Before: "Project API", After: "Project API v2"}}},
+		{name: "Backward", query: "?from=2&to=1", expectedCode: http.StatusOK, expectedChanges: []domain.FieldChange{{Field: "name", Before: "Project API v2", After: "Project API"}}},
+		{name: "Same version", query: "?from=2&to=2", expectedCode: http.StatusOK, expectedChanges: []domain.FieldChange{}},
+		{name: "Not Found", query: "?from=1&to=3", expectedCode: http.StatusNotFound},
+		{name: "Missing to", query: "?from=1", expectedCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId/diff")
+			c.SetParamNames("projectId")
+			c.SetParamValues(project.ID.Hex())
+
+			// Assertions
+			handle(c, DiffProject)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				response := domain.ProjectDiff{}
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, project.ID.Hex(), response.ProjectID)
+				assert.Equal(t, tt.expectedChanges, response.Changes)
+			}
+		})
+	}
+}
+
+func TestRevertProject(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      string
+		deleted      bool
+		expectedCode int
+	}{
+		{name: "Reverted", version: "1", expectedCode: http.StatusOK},
+		{name: "Version Not Found", version: "3", expectedCode: http.StatusNotFound},
+		{name: "Deleted", version: "1", deleted: true, expectedCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			repo := useMemoryProjectRepository(t)
+			project, _ := versionedProject(t, repo)
+			if tt.deleted {
+				assert.NoError(t, repo.Delete(context.Background(), project.ID.Hex(), "alice"))
+			}
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set(HeaderXUser, "bob")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/project-api/v1/project/:projectId/versions/:version/revert")
+			c.SetParamNames("projectId", "version")
+			c.SetParamValues(project.ID.Hex(), tt.version)
+
+			// Assertions
+			handle(c, Actor(RevertProject))
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				response := domain.Project{}
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, "Project API", response.Name)
+				//The revert is a new version, made by the actor of the request
+				version, err := repo.Versions().Get(context.Background(), project.ID.Hex(), 3)
+				if assert.NoError(t, err) {
+					assert.Equal(t, "Project API", version.Project.Name)
+					assert.Equal(t, "bob", version.CreatedBy)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/random"
+)
+
+const (
+	//maxRequestIDLength limits the size of a client provided X-Request-ID
+	maxRequestIDLength = 128
+)
+
+//RequestID middleware accepts the client provided X-Request-ID (or generates a new one), returns it on the response and
+//stores it in the request scoped context, making it available to every layer
+func RequestID(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := c.Request()
+		requestID := request.Header.Get(echo.HeaderXRequestID)
+		if !validRequestID(requestID) {
+			requestID = random.String(32)
+		}
+		c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+		c.SetRequest(request.WithContext(appcontext.WithRequestID(request.Context(), requestID)))
+		return next(c)
+	}
+}
+
+//validRequestID accepts only non empty printable ASCII IDs with a reasonable size, avoiding log injection
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, character := range requestID {
+		if character < '!' || character > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDGenerated(t *testing.T) {
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	var contextRequestID string
+	h := RequestID(func(c echo.Context) error {
+		contextRequestID = appcontext.GetRequestID(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	// Assertions
+	if assert.NoError(t, h(c)) {
+		assert.NotEmpty(t, contextRequestID)
+		assert.Equal(t, contextRequestID, rec.Header().Get(echo.HeaderXRequestID))
+	}
+}
+
+func TestRequestIDAccepted(t *testing.T) {
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderXRequestID, "client-request-id")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	var contextRequestID string
+	h := RequestID(func(c echo.Context) error {
+		contextRequestID = appcontext.GetRequestID(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	// Assertions
+	if assert.NoError(t, h(c)) {
+		assert.Equal(t, "client-request-id", contextRequestID)
+		assert.Equal(t, "client-request-id", rec.Header().Get(echo.HeaderXRequestID))
+	}
+}
+
+func TestRequestIDRejectsInvalid(t *testing.T) {
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderXRequestID, "forged\nlog line")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := RequestID(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	// Assertions
+	if assert.NoError(t, h(c)) {
+		assert.NotEqual(t, "forged\nlog line", rec.Header().Get(echo.HeaderXRequestID))
+		assert.NotEmpty(t, rec.Header().Get(echo.HeaderXRequestID))
+	}
+}
+
+func TestProblemIncludesRequestID(t *testing.T) {
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderXRequestID, "client-request-id")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := RequestID(func(c echo.Context) error {
+		return domain.NotFound("Could not find Project")
+	})
+
+	// Assertions
+	handle(c, h)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	response := domain.Problem{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "client-request-id", response.RequestID)
+	assert.Equal(t, "Could not find Project", response.Detail)
+}
@@ -9,22 +9,41 @@ import (
 
 //MapRoutes for the endpoints which the API listens for
 func MapRoutes(e *echo.Echo) {
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.Use(RequestID)
+	e.Use(Actor)
+	e.Use(Tracing)
+	e.Use(middleware.Recover())
 	g := e.Group("/project-api/v1")
 	if config.Values.UsePrometheus {
 		p := prometheus.NewPrometheus("echo", nil)
 		p.Use(e)
 	}
 	g.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, echo.HeaderContentType},
-		AllowMethods: []string{echo.GET, echo.HEAD, echo.PUT, echo.POST, echo.DELETE, echo.OPTIONS},
+		AllowOrigins:  []string{"*"},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, echo.HeaderContentType, echo.HeaderXRequestID, HeaderXUser, HeaderXActorToken},
+		ExposeHeaders: []string{echo.HeaderXRequestID},
+		AllowMethods:  []string{echo.GET, echo.HEAD, echo.PUT, echo.POST, echo.DELETE, echo.OPTIONS},
 	}))
 
 	g.GET("/health", CheckHealth)
+	g.GET("/health/live", CheckLiveness)
+	g.GET("/health/ready", CheckReadiness)
 	g.GET("/info", GetInfo)
-g.GET("/project", GetProjectList)
-g.POST("/project", CreateProject)
-g.GET("/project/:projectId", GetProject)
-g.PUT("/project/:projectId", UpdateProject)
-g.DELETE("/project/:projectId", DeleteProject)
-}
\ No newline at end of file
+	admin := g.Group("/admin", AdminAuth)
+	admin.GET("/components", GetComponents)
+	admin.GET("/audit", SearchAudit)
+	g.GET("/project", GetProjectList)
+	g.POST("/project", CreateProject)
+	g.GET("/project/trash", GetProjectTrash)
+	g.GET("/project/:projectId", GetProject)
+	g.GET("/project/:projectId/price", PriceProject)
+	g.POST("/project/:projectId/quote", QuoteProject)
+	g.PUT("/project/:projectId", UpdateProject)
+	g.DELETE("/project/:projectId", DeleteProject)
+	g.POST("/project/:projectId/restore", RestoreProject)
+	g.GET("/project/:projectId/history", GetProjectHistory)
+	g.GET("/project/:projectId/versions/:version", GetProjectVersion)
+	g.POST("/project/:projectId/versions/:version/revert", RevertProject)
+	g.GET("/project/:projectId/diff", DiffProject)
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danilovalente/project-api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingContinuesTraceparent(t *testing.T) {
+	// Setup
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/project-api/v1/info", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/project-api/v1/info")
+	var handlerSpanContext trace.SpanContext
+	h := Tracing(func(c echo.Context) error {
+		handlerSpanContext = trace.SpanContextFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	// Assertions
+	if assert.NoError(t, h(c)) {
+		spans := recorder.Ended()
+		if assert.Len(t, spans, 1) {
+			assert.Equal(t, "GET /project-api/v1/info", spans[0].Name())
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+			assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+			assert.Equal(t, spans[0].SpanContext().SpanID(), handlerSpanContext.SpanID())
+		}
+	}
+}
+
+func TestTracingRecordsTheHandlerErrors(t *testing.T) {
+	// Setup
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	tests := []struct {
+		name           string
+		err            error
+		expectedCode   int
+		expectedStatus codes.Code
+	}{
+		{name: "Domain error", err: domain.NotFound("Could not find Project"), expectedCode: http.StatusNotFound, expectedStatus: codes.Unset},
+		{name: "Internal error", err: domain.InternalError("Database error"), expectedCode: http.StatusInternalServerError, expectedStatus: codes.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/project-api/v1/project/1", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			h := Tracing(func(c echo.Context) error {
+				return tt.err
+			})
+
+			// Assertions
+			if assert.NoError(t, h(c)) {
+				assert.Equal(t, tt.expectedCode, rec.Code)
+				assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+				spans := recorder.Ended()
+				span := spans[len(spans)-1]
+				if assert.Len(t, span.Events(), 1) {
+					assert.Equal(t, "exception", span.Events()[0].Name)
+				}
+				assert.Equal(t, tt.expectedStatus, span.Status().Code)
+			}
+		})
+	}
+}
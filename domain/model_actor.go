@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/danilovalente/project-api/appcontext"
+)
+
+//AnonymousActor identifies the changes made by the requests which do not inform their actor
+const AnonymousActor = "anonymous"
+
+//SystemActor identifies the changes made by the application itself, like the purge of the trash
+const SystemActor = "system"
+
+//Actor returns the actor (e.g. the user) carried by the context, or the AnonymousActor if there is none
+func Actor(ctx context.Context) string {
+	if actor := appcontext.GetActor(ctx); actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+//ActorVerified reports if the actor carried by the context was authenticated. Otherwise it was only informed by the client
+func ActorVerified(ctx context.Context) bool {
+	return appcontext.GetActor(ctx) != "" && appcontext.IsActorVerified(ctx)
+}
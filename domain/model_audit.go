@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//AuditEntityProject identifies the AuditEntry of the Projects
+const AuditEntityProject = "project"
+
+//List of consts containing the actions recorded by the AuditEntry
+const (
+	AuditCreated  = "created"
+	AuditUpdated  = "updated"
+	AuditDeleted  = "deleted"
+	AuditRestored = "restored"
+	AuditPurged   = "purged"
+)
+
+//FieldChange is the value of a field before and after a change, written as text. A missing value is empty
+type FieldChange struct {
+	//Field is the path of the field in the JSON representation (e.g. unitPrice.amount or pricingTiers[0].from)
+	Field string `bson:"field" json:"field"`
+
+	Before string `bson:"before,omitempty" json:"before,omitempty"`
+
+	After string `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+//AuditEntry records who changed an entity, when and how. It is written by the Repository in the same transaction as the change
+type AuditEntry struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+
+	Entity string `bson:"entity" json:"entity"`
+
+	EntityID string `bson:"entityId" json:"entityId"`
+
+	Action string `bson:"action" json:"action"`
+
+	Actor string `bson:"actor" json:"actor"`
+
+	//ActorVerified is false when the actor was only informed by the client, without being authenticated
+	ActorVerified bool `bson:"actorVerified" json:"actorVerified"`
+
+	RequestID string `bson:"requestId,omitempty" json:"requestId,omitempty"`
+
+	OccurredAt time.Time `bson:"occurredAt" json:"occurredAt"`
+
+	Changes []FieldChange `bson:"changes" json:"changes"`
+}
+
+//NewProjectAuditEntry creates the AuditEntry of the change of the Project from before (nil when it is created) to after
+//(nil when it is purged), made by the actor of the context
+func NewProjectAuditEntry(ctx context.Context, action string, before *Project, after *Project) AuditEntry {
+	project := after
+	if project == nil {
+		project = before
+	}
+	return AuditEntry{
+		ID:            primitive.NewObjectID(),
+		Entity:        AuditEntityProject,
+		EntityID:      project.ID.Hex(),
+		Action:        action,
+		Actor:         Actor(ctx),
+		ActorVerified: ActorVerified(ctx),
+		RequestID:     appcontext.GetRequestID(ctx),
+		OccurredAt:    time.Now().UTC().Truncate(time.Millisecond),
+		Changes:       DiffProjects(before, after),
+	}
+}
+
+//DiffProjects lists the changed fields of the Project, sorted by their path. The dates kept by the Repository are ignored, but the deletion marker
+func DiffProjects(before *Project, after *Project) []FieldChange {
+	beforeFields, afterFields := auditedFields(before), auditedFields(after)
+	changes := make([]FieldChange, 0)
+	for field, value := range afterFields {
+		if beforeFields[field] != value {
+			changes = append(changes, FieldChange{Field: field, Before: beforeFields[field], After: value})
+		}
+	}
+	for field, value := range beforeFields {
+		if _, ok := afterFields[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, Before: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+//auditedFields writes the non-empty fields of the Project as text, keyed by their path
+func auditedFields(project *Project) map[string]string {
+	fields := make(map[string]string)
+	if project == nil {
+		return fields
+	}
+	put := func(field string, value string) {
+		if value != "" {
+			fields[field] = value
+		}
+	}
+	putMoney := func(path string, money Money) {
+		put(path+".amount", money.Decimal())
+		put(path+".currency", money.Currency)
+	}
+	put("name", project.Name)
+	putMoney("unitPrice", project.UnitPrice)
+	put("timeUnit", project.TimeUnit)
+	for index, tier := range project.PricingTiers {
+		path := fmt.Sprintf("pricingTiers[%d]", index)
+		put(path+".from", tier.From)
+		putMoney(path+".unitPrice", tier.UnitPrice)
+	}
+	put("discount", project.Discount)
+	if project.Deleted() {
+		put("deletedAt", project.DeletedAt.UTC().Format(time.RFC3339Nano))
+	}
+	put("deletedBy", project.DeletedBy)
+	return fields
+}
+
+//AuditQuery filters the AuditEntry, ordered by ID (the order they were recorded). The empty filters match every entry
+type AuditQuery struct {
+	Entity string
+
+	EntityID string
+
+	Actor string
+
+	//From is the first moment, inclusive, in which the entries occurred
+	From time.Time
+
+	//Until is the last moment, exclusive, in which the entries occurred
+	Until time.Time
+
+	//LastEntryID is the last entry of the previous page
+	LastEntryID string
+
+	PageSize int64
+}
+
+//Matches checks if the entry passes the filters of the query, but the paging ones
+func (query *AuditQuery) Matches(entry *AuditEntry) bool {
+	return (query.Entity == "" || query.Entity == entry.Entity) &&
+		(query.EntityID == "" || query.EntityID == entry.EntityID) &&
+		(query.Actor == "" || query.Actor == entry.Actor) &&
+		(query.From.IsZero() || !entry.OccurredAt.Before(query.From)) &&
+		(query.Until.IsZero() || entry.OccurredAt.Before(query.Until))
+}
+
+//AuditRepository is the specification of the features delivered by a Repository for the AuditEntry.
+//The entries are written by the Repositories of the audited entities
+type AuditRepository interface {
+	appcontext.Component
+	Find(ctx context.Context, query AuditQuery) ([]*AuditEntry, error)
+}
+
+type ProjectHistoryUsecase interface {
+	Execute(ctx context.Context, ID string, lastEntryID string, pageSize int64) ([]*AuditEntry, error)
+}
+
+type AuditSearchUsecase interface {
+	Execute(ctx context.Context, query AuditQuery) ([]*AuditEntry, error)
+}
+
+//GetAuditRepository gets the AuditRepository current implementation
+func GetAuditRepository() AuditRepository {
+	return appcontext.Current.Get(appcontext.AuditRepository).(AuditRepository)
+}
+
+//GetProjectHistoryUsecase gets the ProjectHistoryUsecase current implementation
+func GetProjectHistoryUsecase() ProjectHistoryUsecase {
+	return appcontext.Current.Get(appcontext.ProjectHistoryUsecase).(ProjectHistoryUsecase)
+}
+
+//GetAuditSearchUsecase gets the AuditSearchUsecase current implementation
+func GetAuditSearchUsecase() AuditSearchUsecase {
+	return appcontext.Current.Get(appcontext.AuditSearchUsecase).(AuditSearchUsecase)
+}
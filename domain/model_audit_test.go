@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDiffProjects(t *testing.T) {
+	before := Project{
+		ID:           primitive.NewObjectID(),
+		Name:         "Project API",
+		UnitPrice:    Money{Amount: 10000, Currency: "EUR"},
+		TimeUnit:     TimeUnitHour,
+		PricingTiers: []PricingTier{{From: "10", UnitPrice: Money{Amount: 9000, Currency: "EUR"}}},
+		DateCreated:  time.Now(),
+	}
+	after := before
+	after.Name = "Project API v2"
+	after.UnitPrice = Money{Amount: 12050, Currency: "EUR"}
+	after.PricingTiers = nil
+	after.Discount = "5"
+	after.DateUpdated = time.Now()
+
+	assert.Equal(t, []FieldChange{
+		{Field: "discount", After: "5"},
+		{Field: "name", Before: "Project API", After: "Project API v2"},
+		{Field: "pricingTiers[0].from", Before: "10"},
+		{Field: "pricingTiers[0].unitPrice.amount", Before: "90.00"},
+		{Field: "pricingTiers[0].unitPrice.currency", Before: "EUR"},
+		{Field: "unitPrice.amount", Before: "100.00", After: "120.50"},
+	}, DiffProjects(&before, &after))
+	assert.Empty(t, DiffProjects(&before, &before))
+
+	created := DiffProjects(nil, &before)
+	assert.Contains(t, created, FieldChange{Field: "name", After: "Project API"})
+	assert.Contains(t, created, FieldChange{Field: "timeUnit", After: TimeUnitHour})
+
+	deleted := before
+	deleted.DeletedAt = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	deleted.DeletedBy = "alice"
+	assert.Equal(t, []FieldChange{
+		{Field: "deletedAt", After: "2026-10-19T12:00:00Z"},
+		{Field: "deletedBy", After: "alice"},
+	}, DiffProjects(&before, &deleted))
+}
+
+func TestNewProjectAuditEntry(t *testing.T) {
+	project := Project{ID: primitive.NewObjectID(), Name: "Project API", UnitPrice: Money{Amount: 10000, Currency: "EUR"}, TimeUnit: TimeUnitHour}
+	ctx := appcontext.WithActor(appcontext.WithRequestID(context.Background(), "request-1"), "alice")
+
+	entry := NewProjectAuditEntry(ctx, AuditCreated, nil, &project)
+	assert.NotEqual(t, primitive.NilObjectID, entry.ID)
+	assert.Equal(t, AuditEntityProject, entry.Entity)
+	assert.Equal(t, project.ID.Hex(), entry.EntityID)
+	assert.Equal(t, AuditCreated, entry.Action)
+	assert.Equal(t, "alice", entry.Actor)
+	assert.Equal(t, "request-1", entry.RequestID)
+	assert.False(t, entry.OccurredAt.IsZero())
+	assert.NotEmpty(t, entry.Changes)
+
+	assert.Equal(t, AnonymousActor, NewProjectAuditEntry(context.Background(), AuditCreated, nil, &project).Actor)
+}
+
+func TestAuditQueryMatches(t *testing.T) {
+	occurredAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	entry := AuditEntry{Entity: AuditEntityProject, EntityID: "1", Actor: "alice", OccurredAt: occurredAt}
+	tests := []struct {
+		name     string
+		query    AuditQuery
+		expected bool
+	}{
+		{name: "No filter", query: AuditQuery{}, expected: true},
+		{name: "All filters", query: AuditQuery{Entity: AuditEntityProject, EntityID: "1", Actor: "alice", From: occurredAt, Until: occurredAt.Add(time.Second)}, expected: true},
+		{name: "Other entity", query: AuditQuery{Entity: "taxRate"}},
+		{name: "Other entity ID", query: AuditQuery{EntityID: "2"}},
+		{name: "Other actor", query: AuditQuery{Actor: "bob"}},
+		{name: "Before from", query: AuditQuery{From: occurredAt.Add(time.Second)}},
+		{name: "Until is exclusive", query: AuditQuery{Until: occurredAt}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.query.Matches(&entry))
+		})
+	}
+}
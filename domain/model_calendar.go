@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/danilovalente/project-api/appcontext"
+)
+
+//List of consts containing the time units in which the Projects are priced
+const (
+	TimeUnitHour  = "Hour"
+	TimeUnitDay   = "Day"
+	TimeUnitWeek  = "Week"
+	TimeUnitMonth = "Month"
+)
+
+//TimeUnits lists the time units accepted by the Project, from the shortest to the longest
+var TimeUnits = []string{TimeUnitHour, TimeUnitDay, TimeUnitWeek, TimeUnitMonth}
+
+//ValidTimeUnit checks if the unit is any of the TimeUnits
+func ValidTimeUnit(unit string) bool {
+	for _, timeUnit := range TimeUnits {
+		if unit == timeUnit {
+			return true
+		}
+	}
+	return false
+}
+
+//Calendar holds the working time in each time unit, converting the quantities between them
+type Calendar struct {
+	hoursPerDay   *big.Rat
+	daysPerWeek   *big.Rat
+	weeksPerMonth *big.Rat
+}
+
+//NewCalendar creates the Calendar from the decimal number of working hours per day, working days per week and weeks per month
+func NewCalendar(hoursPerDay string, daysPerWeek string, weeksPerMonth string) (*Calendar, error) {
+	calendar := &Calendar{}
+	for _, setting := range []struct {
+		field   string
+		decimal string
+		target  **big.Rat
+	}{
+		{field: "hoursPerDay", decimal: hoursPerDay, target: &calendar.hoursPerDay},
+		{field: "daysPerWeek", decimal: daysPerWeek, target: &calendar.daysPerWeek},
+		{field: "weeksPerMonth", decimal: weeksPerMonth, target: &calendar.weeksPerMonth},
+	} {
+		value, err := ParseDecimal(setting.decimal)
+		if err != nil {
+			return nil, FieldViolation(setting.field, ViolationFormat, setting.field, setting.decimal)
+		}
+		if value.Sign() <= 0 {
+			return nil, FieldViolation(setting.field, ViolationPositive, setting.field)
+		}
+		*setting.target = value
+	}
+	return calendar, nil
+}
+
+//hours returns the working hours in one of the unit
+func (calendar *Calendar) hours(unit string) *big.Rat {
+	hours := big.NewRat(1, 1)
+	switch unit {
+	case TimeUnitMonth:
+		hours.Mul(hours, calendar.weeksPerMonth)
+		fallthrough
+	case TimeUnitWeek:
+		hours.Mul(hours, calendar.daysPerWeek)
+		fallthrough
+	case TimeUnitDay:
+		hours.Mul(hours, calendar.hoursPerDay)
+	}
+	return hours
+}
+
+//Convert the exact quantity in the unit from to the unit to (e.g. 3 Week to 120 Hour)
+func (calendar *Calendar) Convert(quantity *big.Rat, from string, to string) (*big.Rat, error) {
+	for _, unit := range []string{from, to} {
+		if !ValidTimeUnit(unit) {
+			return nil, FieldViolation("timeUnit", ViolationOneOf, "timeUnit", strings.Join(TimeUnits, ", "))
+		}
+	}
+	converted := new(big.Rat).Mul(quantity, calendar.hours(from))
+	return converted.Quo(converted, calendar.hours(to)), nil
+}
+
+//QuoteRequest asks for the price of a quantity of work in any of the TimeUnits
+type QuoteRequest struct {
+	//Quantity is a decimal number, sent as a JSON number or string (e.g. 3 or "2.5")
+	Quantity QuoteQuantity `json:"quantity"`
+
+	Unit string `json:"unit"`
+}
+
+//QuoteQuantity reads a decimal quantity from a JSON number or string, keeping its exact digits
+type QuoteQuantity string
+
+//UnmarshalJSON accepts both the JSON numbers and strings, leaving the validation of the digits to the Usecase.
+//Any other JSON value (object, array, bool or null) is reported as a type violation.
+func (quantity *QuoteQuantity) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) == 0:
+		*quantity = ""
+	case data[0] == '"':
+		var decimal string
+		if err := json.Unmarshal(data, &decimal); err != nil {
+			return err
+		}
+		*quantity = QuoteQuantity(decimal)
+	case data[0] == '-' || (data[0] >= '0' && data[0] <= '9'):
+		*quantity = QuoteQuantity(data)
+	default:
+		return FieldViolation("quantity", ViolationType, "quantity", "number", jsonKind(data[0]))
+	}
+	return nil
+}
+
+//Quote is the price of the requested quantity, converted to the TimeUnit of the Project
+type Quote struct {
+	Quantity string `json:"quantity"`
+
+	Unit string `json:"unit"`
+
+	//ConvertedQuantity is the Quantity in the TimeUnit, rounded to 6 decimal places for display only
+	ConvertedQuantity string `json:"convertedQuantity"`
+
+	TimeUnit string `json:"timeUnit"`
+
+	UnitPrice Money `json:"unitPrice"`
+
+	Total Money `json:"total"`
+
+	//Breakdown itemizes the Total per pricing tier, with the discount of the Project
+	Breakdown PriceBreakdown `json:"breakdown"`
+}
+
+//Localized returns a copy of the Quote with the amounts displayed following the conventions of the Locale
+func (quote Quote) Localized(locale *Locale) Quote {
+	quote.UnitPrice = quote.UnitPrice.Localized(locale)
+	quote.Total = quote.Total.Localized(locale)
+	quote.Breakdown = quote.Breakdown.Localized(locale)
+	return quote
+}
+
+type ProjectQuoteUsecase interface {
+	Execute(ctx context.Context, ID string, request QuoteRequest) (*Quote, error)
+}
+
+//GetProjectQuoteUsecase gets the ProjectQuoteUsecase current implementation
+func GetProjectQuoteUsecase() ProjectQuoteUsecase {
+	return appcontext.Current.Get(appcontext.ProjectQuoteUsecase).(ProjectQuoteUsecase)
+}
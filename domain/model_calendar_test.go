@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarConvert(t *testing.T) {
+	calendar, err := NewCalendar("8", "5", "4.345")
+	if !assert.NoError(t, err) {
+		return
+	}
+	tests := []struct {
+		name     string
+		quantity string
+		from     string
+		to       string
+		expected string
+	}{
+		{name: "Same unit", quantity: "3", from: TimeUnitWeek, to: TimeUnitWeek, expected: "3"},
+		{name: "Weeks to hours", quantity: "3", from: TimeUnitWeek, to: TimeUnitHour, expected: "120"},
+		{name: "Hours to days", quantity: "12", from: TimeUnitHour, to: TimeUnitDay, expected: "1.5"},
+		{name: "Month to hours", quantity: "1", from: TimeUnitMonth, to: TimeUnitHour, expected: "173.8"},
+		{name: "Days to months", quantity: "10", from: TimeUnitDay, to: TimeUnitMonth, expected: "0.460299"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quantity, _ := ParseDecimal(tt.quantity)
+			converted, err := calendar.Convert(quantity, tt.from, tt.to)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, FormatDecimal(converted))
+			}
+		})
+	}
+
+	//The conversions are exact, so converting back returns the same quantity
+	converted, _ := calendar.Convert(big.NewRat(10, 1), TimeUnitDay, TimeUnitMonth)
+	back, _ := calendar.Convert(converted, TimeUnitMonth, TimeUnitDay)
+	assert.Equal(t, 0, back.Cmp(big.NewRat(10, 1)))
+
+	_, err = calendar.Convert(big.NewRat(1, 1), "Year", TimeUnitHour)
+	if assert.IsType(t, ConstraintViolationError{}, err) && assert.Len(t, err.(ConstraintViolationError).Violations, 1) {
+		violation := err.(ConstraintViolationError).Violations[0]
+		assert.Equal(t, "timeUnit", violation.Field)
+		assert.Equal(t, ViolationOneOf, violation.Code)
+	}
+}
+
+func TestNewCalendar(t *testing.T) {
+	tests := []struct {
+		name          string
+		hoursPerDay   string
+		daysPerWeek   string
+		weeksPerMonth string
+		expectedField string
+		expectedCode  string
+	}{
+		{name: "Valid", hoursPerDay: "7.5", daysPerWeek: "5", weeksPerMonth: "4"},
+		{name: "Zero", hoursPerDay: "0", daysPerWeek: "5", weeksPerMonth: "4", expectedField: "hoursPerDay", expectedCode: ViolationPositive},
+		{name: "Invalid", hoursPerDay: "8", daysPerWeek: "five", weeksPerMonth: "4", expectedField: "daysPerWeek", expectedCode: ViolationFormat},
+		{name: "Missing", hoursPerDay: "8", daysPerWeek: "5", weeksPerMonth: "", expectedField: "weeksPerMonth", expectedCode: ViolationFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCalendar(tt.hoursPerDay, tt.daysPerWeek, tt.weeksPerMonth)
+			if tt.expectedCode == "" {
+				assert.NoError(t, err)
+			} else if violation, ok := err.(ConstraintViolationError); assert.True(t, ok) && assert.Len(t, violation.Violations, 1) {
+				assert.Equal(t, tt.expectedField, violation.Violations[0].Field)
+				assert.Equal(t, tt.expectedCode, violation.Violations[0].Code)
+			}
+		})
+	}
+}
+
+func TestQuoteQuantityUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		expectedQuantity QuoteQuantity
+		expectedKind     string
+	}{
+		{name: "Number", body: `{"quantity":2.5}`, expectedQuantity: "2.5"},
+		{name: "String", body: `{"quantity":"2.5"}`, expectedQuantity: "2.5"},
+		{name: "Escaped string", body: `{"quantity":"\u0032"}`, expectedQuantity: "2"},
+		{name: "Missing", body: `{}`, expectedQuantity: ""},
+		{name: "Object", body: `{"quantity":{"value":2}}`, expectedKind: "object"},
+		{name: "Array", body: `{"quantity":[2]}`, expectedKind: "array"},
+		{name: "Bool", body: `{"quantity":true}`, expectedKind: "bool"},
+		{name: "Null", body: `{"quantity":null}`, expectedKind: "null"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := QuoteRequest{}
+			err := json.Unmarshal([]byte(tt.body), &request)
+			if tt.expectedKind == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedQuantity, request.Quantity)
+			} else if violation, ok := err.(ConstraintViolationError); assert.True(t, ok) && assert.Len(t, violation.Violations, 1) {
+				assert.Equal(t, "quantity", violation.Violations[0].Field)
+				assert.Equal(t, ViolationType, violation.Violations[0].Code)
+				assert.Equal(t, "The 'quantity' must be a number, not a "+tt.expectedKind, violation.Violations[0].Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+//Component represents a component registered in the Application Context and its current state
+type Component struct {
+	Name string `json:"name"`
+
+	State string `json:"state"`
+
+	Dependencies []string `json:"dependencies"`
+
+	Error string `json:"error,omitempty"`
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "strings"
+
 //IdentifiableError represents an error identifiable by its Code
 type IdentifiableError interface {
 	GetCode() int
@@ -10,6 +12,8 @@ type GenericError struct {
 	Code int `json:"code"`
 
 	Message string `json:"message"`
+
+	RequestID string `json:"requestId,omitempty"`
 }
 
 func (e GenericError) Error() string {
@@ -21,9 +25,22 @@ func (e GenericError) GetCode() int {
 	return e.Code
 }
 
+//GetType identifies the problem. A GenericError is an unexpected error
+func (e GenericError) GetType() string {
+	return ProblemTypeInternalError
+}
+
 //AlreadyExistsError represents an specialized Already Exists Error
 type AlreadyExistsError struct {
 	GenericError
+
+	//Field which value conflicts with an existent entity, when it is known
+	Field string `json:"field,omitempty"`
+}
+
+//GetType identifies the problem
+func (e AlreadyExistsError) GetType() string {
+	return ProblemTypeAlreadyExists
 }
 
 //AlreadyExists builds an specialized Already Exists Error
@@ -34,6 +51,13 @@ func AlreadyExists(message string) AlreadyExistsError {
 	return alreadyExists
 }
 
+//AlreadyExistsForField builds an specialized Already Exists Error caused by the value of the field
+func AlreadyExistsForField(field string, message string) AlreadyExistsError {
+	alreadyExists := AlreadyExists(message)
+	alreadyExists.Field = field
+	return alreadyExists
+}
+
 //InternalError builds an specialized Internal Error
 func InternalError(message string) GenericError {
 	internalError := GenericError{}
@@ -45,6 +69,35 @@ func InternalError(message string) GenericError {
 //ConstraintViolationError represents an specialized Constraint Violation Error
 type ConstraintViolationError struct {
 	GenericError
+
+	//Violations lists every invalid field, when they are known
+	Violations []Violation `json:"violations,omitempty"`
+
+	//messageKey identifies the message in the catalogs, when it is translatable
+	messageKey string
+}
+
+//GetType identifies the problem
+func (e ConstraintViolationError) GetType() string {
+	return ProblemTypeConstraintViolation
+}
+
+//Localize translates the message and the violations of the error. The messages without translation are kept
+func (e ConstraintViolationError) Localize(locale *Locale) ConstraintViolationError {
+	violations := make([]Violation, len(e.Violations))
+	for index, violation := range e.Violations {
+		violations[index] = violation.Localize(locale)
+	}
+	switch {
+	case e.messageKey != "":
+		e.Message = describeViolations(locale.Message(e.messageKey), violations)
+	case len(violations) == 1 && e.Message == e.Violations[0].Message:
+		e.Message = violations[0].Message
+	}
+	if e.Violations != nil {
+		e.Violations = violations
+	}
+	return e
 }
 
 //ConstraintViolation builds an specialized Constraint Violation Error
@@ -55,11 +108,40 @@ func ConstraintViolation(message string) ConstraintViolationError {
 	return constraintViolation
 }
 
+//ConstraintViolations builds an specialized Constraint Violation Error listing the invalid fields. The message of the key is followed by the violation messages
+func ConstraintViolations(messageKey string, violations ...Violation) ConstraintViolationError {
+	constraintViolation := ConstraintViolation(describeViolations(English.Message(messageKey), violations))
+	constraintViolation.Violations = violations
+	constraintViolation.messageKey = messageKey
+	return constraintViolation
+}
+
+//FieldViolation builds an specialized Constraint Violation Error caused by a single field, described by the message of the code
+func FieldViolation(field string, code string, args ...interface{}) ConstraintViolationError {
+	violation := NewViolation(field, code, args...)
+	constraintViolation := ConstraintViolation(violation.Message)
+	constraintViolation.Violations = []Violation{violation}
+	return constraintViolation
+}
+
+func describeViolations(message string, violations []Violation) string {
+	messages := []string{message}
+	for _, violation := range violations {
+		messages = append(messages, violation.Message)
+	}
+	return strings.Join(messages, ". ")
+}
+
 //NotFoundError represents an specialized Not Found Error
 type NotFoundError struct {
 	GenericError
 }
 
+//GetType identifies the problem
+func (e NotFoundError) GetType() string {
+	return ProblemTypeNotFound
+}
+
 //NotFound builds an specialized Not Found Error
 func NotFound(message string) NotFoundError {
 	notFound := NotFoundError{}
@@ -67,3 +149,21 @@ func NotFound(message string) NotFoundError {
 	notFound.Message = message
 	return notFound
 }
+
+//ServiceUnavailableError represents an specialized Service Unavailable Error, raised while a dependency (e.g. the database) is down
+type ServiceUnavailableError struct {
+	GenericError
+}
+
+//GetType identifies the problem
+func (e ServiceUnavailableError) GetType() string {
+	return ProblemTypeServiceUnavailable
+}
+
+//ServiceUnavailable builds an specialized Service Unavailable Error
+func ServiceUnavailable(message string) ServiceUnavailableError {
+	serviceUnavailable := ServiceUnavailableError{}
+	serviceUnavailable.Code = 503
+	serviceUnavailable.Message = message
+	return serviceUnavailable
+}
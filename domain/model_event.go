@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+)
+
+const (
+	//ProjectCreatedEvent is published after a Project is created
+	ProjectCreatedEvent = "ProjectCreated"
+	//ProjectUpdatedEvent is published after a Project is updated
+	ProjectUpdatedEvent = "ProjectUpdated"
+	//ProjectDeletedEvent is published after a Project is deleted
+	ProjectDeletedEvent = "ProjectDeleted"
+	//ProjectRestoredEvent is published after a Project is restored from the trash
+	ProjectRestoredEvent = "ProjectRestored"
+)
+
+//ProjectEvent represents a change of a Project, published to the other application instances
+type ProjectEvent struct {
+	Type string `bson:"type" json:"type"`
+
+	ProjectID string `bson:"projectId" json:"projectId"`
+
+	RequestID string `bson:"requestId,omitempty" json:"requestId,omitempty"`
+
+	OccurredAt time.Time `bson:"occurredAt" json:"occurredAt"`
+}
+
+//NewProjectEvent creates the event of the Project change, carrying the Request ID of the context
+func NewProjectEvent(ctx context.Context, eventType string, projectID string) ProjectEvent {
+	return ProjectEvent{
+		Type:       eventType,
+		ProjectID:  projectID,
+		RequestID:  appcontext.GetRequestID(ctx),
+		OccurredAt: time.Now(),
+	}
+}
+
+//ProjectEventHandler is called for each ProjectEvent received by a subscriber
+type ProjectEventHandler func(event ProjectEvent)
+
+//EventBus is the specification of the features delivered by a bus of ProjectEvent, shared by the application instances
+type EventBus interface {
+	appcontext.Component
+	//Publish the event to the subscribers of every instance
+	Publish(ctx context.Context, event ProjectEvent) error
+	//Subscribe the handler to the events published from now on
+	Subscribe(handler ProjectEventHandler)
+}
+
+//noopEventBus is used while no EventBus implementation is registered, when the events are not shared by the instances
+type noopEventBus struct{}
+
+func (noopEventBus) Publish(context.Context, ProjectEvent) error { return nil }
+func (noopEventBus) Subscribe(ProjectEventHandler)               {}
+
+//GetEventBus gets the EventBus current implementation
+func GetEventBus() EventBus {
+	eventBus, ok := appcontext.Current.Get(appcontext.EventBus).(EventBus)
+	if !ok {
+		return noopEventBus{}
+	}
+	return eventBus
+}
@@ -1,6 +1,26 @@
 package domain
 
+const (
+	//HealthStatusUp reports a healthy application or component
+	HealthStatusUp = "UP"
+	//HealthStatusDown reports an unhealthy application or component
+	HealthStatusDown = "DOWN"
+)
+
 //Health represents the HealthCheck response
 type Health struct {
 	Status string `json:"status"`
+
+	Components map[string]ComponentHealth `json:"components,omitempty"`
+}
+
+//ComponentHealth represents the result of the HealthCheck of a single component
+type ComponentHealth struct {
+	Status string `json:"status"`
+
+	State string `json:"state,omitempty"`
+
+	LatencyMillis float64 `json:"latencyMs"`
+
+	Error string `json:"error,omitempty"`
 }
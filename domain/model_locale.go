@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+//catalogFiles holds a message catalog per language, named after the language (e.g. pt.json)
+//
+//go:embed messages/*.json
+var catalogFiles embed.FS
+
+//Locale translates the messages identified by the error codes and formats the amounts following the conventions of a language
+type Locale struct {
+	//Language is the ISO 639-1 code of the language (e.g. pt)
+	Language string
+
+	decimalSeparator  string
+	groupingSeparator string
+	symbolFirst       bool
+	symbolSpaced      bool
+	messages          map[string]string
+}
+
+//List of the supported Locales
+var (
+	//English is the default Locale, used when the client accepts none of the supported languages
+	English = &Locale{Language: "en", decimalSeparator: ".", groupingSeparator: ",", symbolFirst: true}
+	//Portuguese formats the amounts as in Brazil
+	Portuguese = &Locale{Language: "pt", decimalSeparator: ",", groupingSeparator: ".", symbolFirst: true, symbolSpaced: true}
+	//Spanish formats the amounts as in Spain
+	Spanish = &Locale{Language: "es", decimalSeparator: ",", groupingSeparator: ".", symbolSpaced: true}
+)
+
+var locales = map[string]*Locale{
+	English.Language:    English,
+	Portuguese.Language: Portuguese,
+	Spanish.Language:    Spanish,
+}
+
+//GetLocale returns the Locale of the language (e.g. pt or pt-BR), or nil if it is not supported
+func GetLocale(language string) *Locale {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if index := strings.IndexAny(language, "-_"); index >= 0 {
+		language = language[:index]
+	}
+	return locales[language]
+}
+
+//Message translates the message identified by the key, filled with the args. The English message is used when the translation is missing
+func (locale *Locale) Message(key string, args ...interface{}) string {
+	message, ok := locale.messages[key]
+	if !ok {
+		message, ok = English.messages[key]
+	}
+	if !ok {
+		message = key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+func loadCatalogs() error {
+	for language, locale := range locales {
+		content, err := catalogFiles.ReadFile(path.Join("messages", language+".json"))
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(content, &locale.messages); err != nil {
+			return fmt.Errorf("Could not parse the message catalog %s: %w", language, err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	if err := loadCatalogs(); err != nil {
+		panic(err)
+	}
+}
@@ -1,12 +1,40 @@
 package domain
 
 import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
 	"github.com/Rhymond/go-money"
 )
 
+//defaultFraction is the count of fraction digits of the amounts in an unknown currency
+const defaultFraction = 2
+
+//decimalPattern matches the decimal amounts (e.g. 1234.50), capturing the sign, the integer and the fraction digits
+var decimalPattern = regexp.MustCompile(`^(-?)([0-9]+)(?:\.([0-9]+))?$`)
+
+//Money is an amount in the minor units (e.g. cents) of an ISO 4217 currency
 type Money struct {
 	Amount   int64  `bson:"amount" json:"amount"`
 	Currency string `bson:"currency" json:"currency"`
+	//Displayed is the amount formatted for the Locale of the client. It is only filled in the responses
+	Displayed string `bson:"-" json:"display,omitempty"`
+
+	//amountViolation is the code of the Violation found while reading the JSON amount, reported by Validate with the amountArgs
+	amountViolation string
+	amountArgs      []interface{}
+}
+
+//moneyJSON is the JSON representation of the Money
+type moneyJSON struct {
+	Amount    json.RawMessage `json:"amount"`
+	Currency  string          `json:"currency"`
+	Formatted string          `json:"formatted,omitempty"`
+	Displayed string          `json:"display,omitempty"`
 }
 
 // NewMoney creates and returns new instance of Money.
@@ -124,6 +152,59 @@ func (m *Money) Multiply(mul int64) *Money {
 	return &Money{Amount: result.Amount(), Currency: result.Currency().Code}
 }
 
+// MultiplyBy returns new Money struct with value representing Self multiplied by the exact factor (e.g. 7.5 hours),
+// rounded to the minor units following the mode.
+func (m *Money) MultiplyBy(factor *big.Rat, mode RoundingMode) (*Money, error) {
+	return m.rounded(new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), factor), mode)
+}
+
+// DivideBy returns new Money struct with value representing Self divided by the exact divisor,
+// rounded to the minor units following the mode.
+func (m *Money) DivideBy(divisor *big.Rat, mode RoundingMode) (*Money, error) {
+	if divisor.Sign() == 0 {
+		return nil, FieldViolation("divisor", ViolationNonZero, "divisor")
+	}
+	return m.rounded(new(big.Rat).Quo(new(big.Rat).SetInt64(m.Amount), divisor), mode)
+}
+
+// Percentage returns new Money struct with value representing the percent of Self (e.g. 12.5 for 12.5%),
+// rounded to the minor units following the mode.
+func (m *Money) Percentage(percent *big.Rat, mode RoundingMode) (*Money, error) {
+	return m.MultiplyBy(new(big.Rat).Quo(percent, big.NewRat(100, 1)), mode)
+}
+
+// RoundCash returns new Money struct with value rounded to the smallest amount paid in cash in the currency (e.g. 0.05 CHF).
+// The value is kept in the currencies which have coins of one minor unit.
+func (m *Money) RoundCash(mode RoundingMode) (*Money, error) {
+	increment, ok := cashIncrements[m.Currency]
+	if !ok {
+		return &Money{Amount: m.Amount, Currency: m.Currency}, nil
+	}
+	return m.RoundToIncrement(increment, mode)
+}
+
+// RoundToIncrement returns new Money struct with value rounded to a multiple of the increment in minor units (e.g. 5 for 0.05),
+// following the mode.
+func (m *Money) RoundToIncrement(increment int64, mode RoundingMode) (*Money, error) {
+	if increment <= 0 {
+		return nil, FieldViolation("increment", ViolationPositive, "increment")
+	}
+	multiples, err := round(big.NewRat(m.Amount, increment), mode)
+	if err != nil {
+		return nil, err
+	}
+	return m.rounded(new(big.Rat).SetInt(multiples.Mul(multiples, big.NewInt(increment))), mode)
+}
+
+// rounded returns new Money struct in the currency of Self with the exact value rounded to the minor units following the mode.
+func (m *Money) rounded(value *big.Rat, mode RoundingMode) (*Money, error) {
+	amount, err := roundToInt64(value, mode)
+	if err != nil {
+		return nil, err
+	}
+	return &Money{Amount: amount, Currency: m.Currency}, nil
+}
+
 // Round returns new Money struct with value rounded to nearest zero.
 func (m *Money) Round() *Money {
 	thisMoney := money.New(m.Amount, m.Currency)
@@ -175,3 +256,189 @@ func (m *Money) AsMajorUnits() float64 {
 	thisMoney := money.New(m.Amount, m.Currency)
 	return thisMoney.AsMajorUnits()
 }
+
+// ParseMoney creates a Money from a decimal amount (e.g. 1234.50), converted with the fraction digits of the currency.
+// Returns a ConstraintViolationError if the amount cannot be represented in the currency.
+func ParseMoney(decimal string, code string) (*Money, error) {
+	m := &Money{Currency: code}
+	amount, violation := parseDecimal(decimal, m.fraction())
+	if violation != "" {
+		return nil, FieldViolation("amount", violation, append([]interface{}{"amount"}, decimalViolationArgs(violation, decimal, m.fraction())...)...)
+	}
+	m.Amount = amount
+	return m, nil
+}
+
+// parseDecimal converts the decimal amount to minor units, returning the code of the Violation when it cannot be represented with the fraction digits.
+func parseDecimal(decimal string, fraction int) (int64, string) {
+	matches := decimalPattern.FindStringSubmatch(strings.TrimSpace(decimal))
+	if matches == nil {
+		return 0, ViolationFormat
+	}
+	sign, integer, decimals := matches[1], matches[2], matches[3]
+	if len(decimals) > fraction {
+		return 0, ViolationPrecision
+	}
+	amount, err := strconv.ParseInt(sign+integer+decimals+strings.Repeat("0", fraction-len(decimals)), 10, 64)
+	if err != nil {
+		return 0, ViolationFormat
+	}
+	return amount, ""
+}
+
+// decimalViolationArgs fill the message of the Violation of a decimal amount, after the field.
+func decimalViolationArgs(violation string, decimal string, fraction int) []interface{} {
+	if violation == ViolationPrecision {
+		return []interface{}{fraction}
+	}
+	return []interface{}{decimal}
+}
+
+// fraction returns the count of fraction digits of the currency.
+func (m *Money) fraction() int {
+	if currency := money.GetCurrency(m.Currency); currency != nil {
+		return currency.Fraction
+	}
+	return defaultFraction
+}
+
+// Validate adds the Violations of the currency and of the amount read from the JSON to the violations, identifying the fields by the path of the Money (e.g. unitPrice).
+// Returns false if the amount could not be read.
+func (m *Money) Validate(path string, violations *Violations) bool {
+	currencyField, amountField := path+".currency", path+".amount"
+	switch {
+	case strings.TrimSpace(m.Currency) == "":
+		violations.Add(currencyField, ViolationRequired, currencyField)
+	case money.GetCurrency(m.Currency) == nil:
+		violations.Add(currencyField, ViolationCurrency, currencyField, m.Currency)
+	}
+	if m.amountViolation != "" {
+		violations.Add(amountField, m.amountViolation, append([]interface{}{amountField}, m.amountArgs...)...)
+		return false
+	}
+	return true
+}
+
+// Decimal represents the amount with the fraction digits of the currency (e.g. 1234.50).
+func (m *Money) Decimal() string {
+	sign, integer, decimals := m.digits()
+	if decimals == "" {
+		return sign + integer
+	}
+	return sign + integer + "." + decimals
+}
+
+// digits splits the amount in its sign, integer and fraction digits.
+func (m *Money) digits() (string, string, string) {
+	fraction := m.fraction()
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	if len(digits) <= fraction {
+		digits = strings.Repeat("0", fraction-len(digits)+1) + digits
+	}
+	return sign, digits[:len(digits)-fraction], digits[len(digits)-fraction:]
+}
+
+// MarshalJSON adds the decimal amount, formatted with the fraction digits of the currency, to the JSON.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{
+		Amount:    json.RawMessage(strconv.FormatInt(m.Amount, 10)),
+		Currency:  m.Currency,
+		Formatted: m.Decimal(),
+		Displayed: m.Displayed,
+	})
+}
+
+// UnmarshalJSON reads the amount in minor units (e.g. 123450) or as a decimal string (e.g. "1234.50"), normalizing the currency to upper case.
+// The invalid amounts are reported by Validate.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	input := moneyJSON{}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return err
+	}
+	*m = Money{Currency: strings.ToUpper(strings.TrimSpace(input.Currency))}
+	amount := bytes.TrimSpace(input.Amount)
+	switch {
+	case len(amount) == 0 || bytes.Equal(amount, []byte("null")):
+	case amount[0] == '"':
+		var decimal string
+		if err := json.Unmarshal(amount, &decimal); err != nil {
+			return err
+		}
+		minorUnits, violation := parseDecimal(decimal, m.fraction())
+		if violation != "" {
+			m.amountViolation = violation
+			m.amountArgs = decimalViolationArgs(violation, decimal, m.fraction())
+			return nil
+		}
+		m.Amount = minorUnits
+	case amount[0] == '-' || (amount[0] >= '0' && amount[0] <= '9'):
+		minorUnits, err := strconv.ParseInt(string(amount), 10, 64)
+		if err != nil {
+			m.amountViolation = ViolationFormat
+			m.amountArgs = []interface{}{string(amount)}
+			return nil
+		}
+		m.Amount = minorUnits
+	default:
+		m.amountViolation = ViolationType
+		m.amountArgs = []interface{}{"number", jsonKind(amount[0])}
+	}
+	return nil
+}
+
+// jsonKind names the kind of the JSON value starting with the character.
+func jsonKind(first byte) string {
+	switch first {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 'n':
+		return "null"
+	default:
+		return "bool"
+	}
+}
+
+// Format represents the Money following the conventions of the Locale (decimal and grouping separators, symbol position),
+// instead of the fixed ones of Display.
+func (m *Money) Format(locale *Locale) string {
+	symbol := m.Currency
+	if currency := money.GetCurrency(m.Currency); currency != nil {
+		symbol = currency.Grapheme
+	}
+	sign, integer, decimals := m.digits()
+
+	var number strings.Builder
+	for index, digit := range integer {
+		if index > 0 && (len(integer)-index)%3 == 0 {
+			number.WriteString(locale.groupingSeparator)
+		}
+		number.WriteRune(digit)
+	}
+	if decimals != "" {
+		number.WriteString(locale.decimalSeparator)
+		number.WriteString(decimals)
+	}
+
+	separator := ""
+	if locale.symbolSpaced {
+		separator = " "
+	}
+	if locale.symbolFirst {
+		return sign + symbol + separator + number.String()
+	}
+	return sign + number.String() + separator + symbol
+}
+
+// Localized returns a copy of the Money displayed following the conventions of the Locale.
+func (m Money) Localized(locale *Locale) Money {
+	m.Displayed = m.Format(locale)
+	return m
+}
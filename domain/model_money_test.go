@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoneyFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		locale   *Locale
+		expected string
+	}{
+		{name: "Grouping", money: Money{Amount: 123456789, Currency: "USD"}, locale: English, expected: "$1,234,567.89"},
+		{name: "Less than one", money: Money{Amount: 5, Currency: "USD"}, locale: English, expected: "$0.05"},
+		{name: "Negative", money: Money{Amount: -100050, Currency: "BRL"}, locale: Portuguese, expected: "-R$ 1.000,50"},
+		{name: "Without fraction", money: Money{Amount: 1234567, Currency: "JPY"}, locale: Spanish, expected: "1.234.567 ¥"},
+		{name: "Three fraction digits", money: Money{Amount: 1234567, Currency: "KWD"}, locale: English, expected: ".د.ك1,234.567"},
+		{name: "Unknown currency", money: Money{Amount: 1000, Currency: "XYZ"}, locale: English, expected: "XYZ10.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.money.Format(tt.locale))
+		})
+	}
+}
+
+func TestLocaleMessage(t *testing.T) {
+	assert.Equal(t, "O Projeto é inválido", Portuguese.Message("project.invalid"))
+	assert.Equal(t, "The 'timeUnit' must be any of [Hour]", English.Message("violation.oneOf", "timeUnit", "Hour"))
+	assert.Equal(t, "unknown.key", Spanish.Message("unknown.key"))
+}
+
+func TestLocaleCatalogsTranslateEveryViolation(t *testing.T) {
+	codes := []string{ViolationRequired, ViolationPositive, ViolationOneOf, ViolationCurrency, ViolationPrecision, ViolationFormat,
+		ViolationType, ViolationSyntax, ViolationMismatch, ViolationRange, ViolationAscending, ViolationNonZero}
+	for language, locale := range locales {
+		for _, code := range codes {
+			_, ok := locale.messages["violation."+code]
+			assert.True(t, ok, "%s has no message for the violation %s", language, code)
+		}
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		decimal           string
+		currency          string
+		expectedAmount    int64
+		expectedViolation string
+	}{
+		{decimal: "1234.50", currency: "EUR", expectedAmount: 123450},
+		{decimal: "1234.5", currency: "EUR", expectedAmount: 123450},
+		{decimal: "1234", currency: "EUR", expectedAmount: 123400},
+		{decimal: "-0.01", currency: "USD", expectedAmount: -1},
+		{decimal: "1234", currency: "JPY", expectedAmount: 1234},
+		{decimal: "1.234", currency: "KWD", expectedAmount: 1234},
+		{decimal: "1.5", currency: "JPY", expectedViolation: ViolationPrecision},
+		{decimal: "1.234", currency: "EUR", expectedViolation: ViolationPrecision},
+		{decimal: "1,234.50", currency: "EUR", expectedViolation: ViolationFormat},
+		{decimal: ".50", currency: "EUR", expectedViolation: ViolationFormat},
+		{decimal: "99999999999999999999", currency: "EUR", expectedViolation: ViolationFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.decimal+" "+tt.currency, func(t *testing.T) {
+			m, err := ParseMoney(tt.decimal, tt.currency)
+			if tt.expectedViolation != "" {
+				if constraintViolation, ok := err.(ConstraintViolationError); assert.True(t, ok) {
+					assert.Equal(t, tt.expectedViolation, constraintViolation.Violations[0].Code)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expectedAmount, m.Amount)
+			}
+		})
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	assert.Equal(t, "1234.50", (&Money{Amount: 123450, Currency: "EUR"}).Decimal())
+	assert.Equal(t, "-0.05", (&Money{Amount: -5, Currency: "USD"}).Decimal())
+	assert.Equal(t, "1234", (&Money{Amount: 1234, Currency: "JPY"}).Decimal())
+	assert.Equal(t, "1.234", (&Money{Amount: 1234, Currency: "KWD"}).Decimal())
+}
@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/danilovalente/project-api/appcontext"
+)
+
+//PricingTier charges its UnitPrice for the quantity above From, up to the From of the next tier.
+//The quantity below the From of the first tier is charged by the UnitPrice of the Project
+type PricingTier struct {
+	//From is the decimal quantity, in the TimeUnit of the Project, in which the tier starts (e.g. 100)
+	From string `bson:"from" json:"from"`
+
+	UnitPrice Money `bson:"unitPrice" json:"unitPrice"`
+}
+
+//PriceLine is the price of the quantity charged by a tier
+type PriceLine struct {
+	From string `json:"from"`
+
+	//To is the quantity in which the next tier starts. Empty in the last tier
+	To string `json:"to,omitempty"`
+
+	Quantity string `json:"quantity"`
+
+	UnitPrice Money `json:"unitPrice"`
+
+	Amount Money `json:"amount"`
+}
+
+//PriceBreakdown itemizes the price of a quantity of a Project per tier
+type PriceBreakdown struct {
+	Quantity string `json:"quantity"`
+
+	TimeUnit string `json:"timeUnit"`
+
+	Lines []PriceLine `json:"lines"`
+
+	Subtotal Money `json:"subtotal"`
+
+	//Discount is the decimal percentage discounted from the Subtotal, if the Project has one
+	Discount string `json:"discount,omitempty"`
+
+	DiscountAmount *Money `json:"discountAmount,omitempty"`
+
+	Total Money `json:"total"`
+}
+
+//validatePricing adds the Violations of the pricing tiers and of the discount to the violations
+func (project *Project) validatePricing(violations *Violations) {
+	if project.Discount != "" {
+		discount, err := ParseDecimal(project.Discount)
+		if err != nil {
+			violations.Add("discount", ViolationFormat, "discount", project.Discount)
+		} else if discount.Sign() < 0 || discount.Cmp(big.NewRat(100, 1)) >= 0 {
+			violations.Add("discount", ViolationRange, "discount", "0", "100")
+		}
+	}
+
+	previousFrom := new(big.Rat)
+	previousField := ""
+	for index := range project.PricingTiers {
+		tier := &project.PricingTiers[index]
+		path := fmt.Sprintf("pricingTiers[%d]", index)
+		fromField := path + ".from"
+		from, err := ParseDecimal(tier.From)
+		switch {
+		case err != nil:
+			violations.Add(fromField, ViolationFormat, fromField, tier.From)
+		case previousField == "" && from.Sign() <= 0:
+			violations.Add(fromField, ViolationPositive, fromField)
+		case previousField != "" && from.Cmp(previousFrom) <= 0:
+			violations.Add(fromField, ViolationAscending, fromField, previousField)
+		}
+		if err == nil {
+			previousFrom, previousField = from, fromField
+		}
+
+		amountField, currencyField := path+".unitPrice.amount", path+".unitPrice.currency"
+		if tier.UnitPrice.Validate(path+".unitPrice", violations) && !tier.UnitPrice.IsPositive() {
+			violations.Add(amountField, ViolationPositive, amountField)
+		}
+		if tier.UnitPrice.Currency != "" && tier.UnitPrice.Currency != project.UnitPrice.Currency {
+			violations.Add(currencyField, ViolationMismatch, currencyField, "unitPrice.currency")
+		}
+	}
+}
+
+//Price the quantity, in the TimeUnit of the Project, charging each tier for the quantity in its range and applying the discount.
+//Each amount is rounded to the minor units following the mode
+func (project *Project) Price(quantity *big.Rat, mode RoundingMode) (*PriceBreakdown, error) {
+	if quantity.Sign() <= 0 {
+		return nil, FieldViolation("quantity", ViolationPositive, "quantity")
+	}
+	breakdown := &PriceBreakdown{
+		Quantity: FormatDecimal(quantity),
+		TimeUnit: project.TimeUnit,
+		Lines:    make([]PriceLine, 0),
+		Subtotal: Money{Currency: project.UnitPrice.Currency},
+	}
+
+	from := new(big.Rat)
+	unitPrice := project.UnitPrice
+	for index := 0; index <= len(project.PricingTiers) && quantity.Cmp(from) > 0; index++ {
+		line := PriceLine{From: FormatDecimal(from), UnitPrice: Money{Amount: unitPrice.Amount, Currency: unitPrice.Currency}}
+		to := quantity
+		var nextUnitPrice Money
+		if index < len(project.PricingTiers) {
+			tierFrom, err := ParseDecimal(project.PricingTiers[index].From)
+			if err != nil {
+				return nil, err
+			}
+			line.To = FormatDecimal(tierFrom)
+			if tierFrom.Cmp(quantity) < 0 {
+				to = tierFrom
+			}
+			nextUnitPrice = project.PricingTiers[index].UnitPrice
+		}
+		lineQuantity := new(big.Rat).Sub(to, from)
+		amount, err := unitPrice.MultiplyBy(lineQuantity, mode)
+		if err != nil {
+			return nil, err
+		}
+		line.Quantity = FormatDecimal(lineQuantity)
+		line.Amount = *amount
+		breakdown.Lines = append(breakdown.Lines, line)
+		breakdown.Subtotal.Amount += amount.Amount
+		from, unitPrice = to, nextUnitPrice
+	}
+
+	breakdown.Total = breakdown.Subtotal
+	if project.Discount != "" {
+		discount, err := ParseDecimal(project.Discount)
+		if err != nil {
+			return nil, err
+		}
+		discountAmount, err := breakdown.Subtotal.Percentage(discount, mode)
+		if err != nil {
+			return nil, err
+		}
+		breakdown.Discount = project.Discount
+		breakdown.DiscountAmount = discountAmount
+		breakdown.Total.Amount -= discountAmount.Amount
+	}
+	return breakdown, nil
+}
+
+//Localized returns a copy of the PriceBreakdown with the amounts displayed following the conventions of the Locale
+func (breakdown PriceBreakdown) Localized(locale *Locale) PriceBreakdown {
+	lines := make([]PriceLine, len(breakdown.Lines))
+	for index, line := range breakdown.Lines {
+		line.UnitPrice = line.UnitPrice.Localized(locale)
+		line.Amount = line.Amount.Localized(locale)
+		lines[index] = line
+	}
+	breakdown.Lines = lines
+	breakdown.Subtotal = breakdown.Subtotal.Localized(locale)
+	if breakdown.DiscountAmount != nil {
+		discountAmount := breakdown.DiscountAmount.Localized(locale)
+		breakdown.DiscountAmount = &discountAmount
+	}
+	breakdown.Total = breakdown.Total.Localized(locale)
+	return breakdown
+}
+
+type ProjectPriceUsecase interface {
+	Execute(ctx context.Context, ID string, quantity string) (*PriceBreakdown, error)
+}
+
+//GetProjectPriceUsecase gets the ProjectPriceUsecase current implementation
+func GetProjectPriceUsecase() ProjectPriceUsecase {
+	return appcontext.Current.Get(appcontext.ProjectPriceUsecase).(ProjectPriceUsecase)
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tieredProject() Project {
+	return Project{
+		Name:      "Project API",
+		UnitPrice: Money{Amount: 10000, Currency: "EUR"},
+		TimeUnit:  "Hour",
+		PricingTiers: []PricingTier{
+			{From: "100", UnitPrice: Money{Amount: 8000, Currency: "EUR"}},
+			{From: "200", UnitPrice: Money{Amount: 6000, Currency: "EUR"}},
+		},
+	}
+}
+
+func TestProjectPrice(t *testing.T) {
+	tests := []struct {
+		name             string
+		quantity         string
+		discount         string
+		expectedLines    []PriceLine
+		expectedSubtotal int64
+		expectedDiscount int64
+		expectedTotal    int64
+	}{
+		{name: "First tier", quantity: "40", expectedLines: []PriceLine{
+			{From: "0", To: "100", Quantity: "40", Amount: Money{Amount: 400000, Currency: "EUR"}},
+		}, expectedSubtotal: 400000, expectedTotal: 400000},
+		{name: "Tier threshold", quantity: "100", expectedLines: []PriceLine{
+			{From: "0", To: "100", Quantity: "100", Amount: Money{Amount: 1000000, Currency: "EUR"}},
+		}, expectedSubtotal: 1000000, expectedTotal: 1000000},
+		{name: "Every tier", quantity: "250.5", expectedLines: []PriceLine{
+			{From: "0", To: "100", Quantity: "100", Amount: Money{Amount: 1000000, Currency: "EUR"}},
+			{From: "100", To: "200", Quantity: "100", Amount: Money{Amount: 800000, Currency: "EUR"}},
+			{From: "200", Quantity: "50.5", Amount: Money{Amount: 303000, Currency: "EUR"}},
+		}, expectedSubtotal: 2103000, expectedTotal: 2103000},
+		{name: "Discount", quantity: "150", discount: "7.5", expectedLines: []PriceLine{
+			{From: "0", To: "100", Quantity: "100", Amount: Money{Amount: 1000000, Currency: "EUR"}},
+			{From: "100", To: "200", Quantity: "50", Amount: Money{Amount: 400000, Currency: "EUR"}},
+		}, expectedSubtotal: 1400000, expectedDiscount: 105000, expectedTotal: 1295000},
+		{name: "Rounded fraction", quantity: "0.333", discount: "10", expectedLines: []PriceLine{
+			{From: "0", To: "100", Quantity: "0.333", Amount: Money{Amount: 3330, Currency: "EUR"}},
+		}, expectedSubtotal: 3330, expectedDiscount: 333, expectedTotal: 2997},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := tieredProject()
+			project.Discount = tt.discount
+			quantity, _ := ParseDecimal(tt.quantity)
+			breakdown, err := project.Price(quantity, RoundHalfEven)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.quantity, breakdown.Quantity)
+				assert.Equal(t, "Hour", breakdown.TimeUnit)
+				if assert.Len(t, breakdown.Lines, len(tt.expectedLines)) {
+					for index, line := range breakdown.Lines {
+						assert.Equal(t, tt.expectedLines[index].From, line.From)
+						assert.Equal(t, tt.expectedLines[index].To, line.To)
+						assert.Equal(t, tt.expectedLines[index].Quantity, line.Quantity)
+						assert.Equal(t, tt.expectedLines[index].Amount, line.Amount)
+					}
+				}
+				assert.Equal(t, Money{Amount: tt.expectedSubtotal, Currency: "EUR"}, breakdown.Subtotal)
+				if tt.discount == "" {
+					assert.Nil(t, breakdown.DiscountAmount)
+				} else {
+					assert.Equal(t, &Money{Amount: tt.expectedDiscount, Currency: "EUR"}, breakdown.DiscountAmount)
+				}
+				assert.Equal(t, Money{Amount: tt.expectedTotal, Currency: "EUR"}, breakdown.Total)
+			}
+		})
+	}
+
+	project := tieredProject()
+	_, err := project.Price(big.NewRat(0, 1), RoundHalfEven)
+	if assert.IsType(t, ConstraintViolationError{}, err) {
+		assert.Equal(t, "quantity", err.(ConstraintViolationError).Violations[0].Field)
+	}
+}
+
+func TestProjectValidPricing(t *testing.T) {
+	tests := []struct {
+		name               string
+		tiers              []PricingTier
+		discount           string
+		expectedViolations []Violation
+	}{
+		{name: "Valid", tiers: tieredProject().PricingTiers, discount: "10"},
+		{name: "Zero discount", discount: "0"},
+		{name: "Invalid discount", discount: "ten", expectedViolations: []Violation{{Field: "discount", Code: ViolationFormat}}},
+		{name: "Full discount", discount: "100", expectedViolations: []Violation{{Field: "discount", Code: ViolationRange}}},
+		{name: "Negative discount", discount: "-5", expectedViolations: []Violation{{Field: "discount", Code: ViolationRange}}},
+		{name: "Tier from zero", tiers: []PricingTier{{From: "0", UnitPrice: Money{Amount: 8000, Currency: "EUR"}}},
+			expectedViolations: []Violation{{Field: "pricingTiers[0].from", Code: ViolationPositive}}},
+		{name: "Tier from invalid", tiers: []PricingTier{{From: "", UnitPrice: Money{Amount: 8000, Currency: "EUR"}}},
+			expectedViolations: []Violation{{Field: "pricingTiers[0].from", Code: ViolationFormat}}},
+		{name: "Tiers not ascending", tiers: []PricingTier{
+			{From: "100", UnitPrice: Money{Amount: 8000, Currency: "EUR"}},
+			{From: "100", UnitPrice: Money{Amount: 6000, Currency: "EUR"}},
+		}, expectedViolations: []Violation{{Field: "pricingTiers[1].from", Code: ViolationAscending}}},
+		{name: "Tier price not positive", tiers: []PricingTier{{From: "100", UnitPrice: Money{Amount: 0, Currency: "EUR"}}},
+			expectedViolations: []Violation{{Field: "pricingTiers[0].unitPrice.amount", Code: ViolationPositive}}},
+		{name: "Tier currency mismatch", tiers: []PricingTier{{From: "100", UnitPrice: Money{Amount: 8000, Currency: "USD"}}},
+			expectedViolations: []Violation{{Field: "pricingTiers[0].unitPrice.currency", Code: ViolationMismatch}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := tieredProject()
+			project.PricingTiers = tt.tiers
+			project.Discount = tt.discount
+			valid, err := project.Valid()
+			if len(tt.expectedViolations) == 0 {
+				assert.True(t, valid)
+				assert.NoError(t, err)
+				return
+			}
+			assert.False(t, valid)
+			if assert.IsType(t, ConstraintViolationError{}, err) {
+				violations := err.(ConstraintViolationError).Violations
+				if assert.Len(t, violations, len(tt.expectedViolations)) {
+					for index, violation := range violations {
+						assert.Equal(t, tt.expectedViolations[index].Field, violation.Field)
+						assert.Equal(t, tt.expectedViolations[index].Code, violation.Code)
+					}
+				}
+			}
+		})
+	}
+}
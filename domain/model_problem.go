@@ -0,0 +1,48 @@
+package domain
+
+//ProblemTypeBaseURI prefixes the stable URIs identifying the problem types reported by the API
+const ProblemTypeBaseURI = "urn:project-api:problem:"
+
+//List of consts containing the problem types of the domain errors
+const (
+	//ProblemTypeBlank - the problem has no additional semantics than its HTTP status (e.g. an unknown route)
+	ProblemTypeBlank = "about:blank"
+	//ProblemTypeInternalError - an unexpected error occurred
+	ProblemTypeInternalError = ProblemTypeBaseURI + "internal-error"
+	//ProblemTypeConstraintViolation - the request is invalid. The violations describe the invalid fields
+	ProblemTypeConstraintViolation = ProblemTypeBaseURI + "constraint-violation"
+	//ProblemTypeNotFound - the requested entity does not exist
+	ProblemTypeNotFound = ProblemTypeBaseURI + "not-found"
+	//ProblemTypeAlreadyExists - the entity conflicts with an existent one
+	ProblemTypeAlreadyExists = ProblemTypeBaseURI + "already-exists"
+	//ProblemTypeServiceUnavailable - a dependency (e.g. the database) is down. The request may be retried later
+	ProblemTypeServiceUnavailable = ProblemTypeBaseURI + "service-unavailable"
+)
+
+//TypedError is an IdentifiableError which also identifies its problem type
+type TypedError interface {
+	error
+	IdentifiableError
+	GetType() string
+}
+
+//Problem describes an error following the Problem Details for HTTP APIs (RFC 7807), extended with the Request ID and the details of the domain errors
+type Problem struct {
+	Type string `json:"type"`
+
+	Title string `json:"title"`
+
+	Status int `json:"status"`
+
+	Detail string `json:"detail,omitempty"`
+
+	Instance string `json:"instance,omitempty"`
+
+	RequestID string `json:"requestId,omitempty"`
+
+	//Field which value conflicts with an existent entity, when it is known
+	Field string `json:"field,omitempty"`
+
+	//Violations lists every invalid field, when they are known
+	Violations []Violation `json:"violations,omitempty"`
+}
@@ -7,8 +7,10 @@
 package domain
 
 import (
+	"context"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/danilovalente/project-api/appcontext"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -24,60 +26,124 @@ type Project struct {
 
 	TimeUnit string `bson:"timeUnit" json:"timeUnit"`
 
+	//PricingTiers replace the UnitPrice above the quantities they start from, in ascending order
+	PricingTiers []PricingTier `bson:"pricingTiers,omitempty" json:"pricingTiers,omitempty"`
+
+	//Discount is the decimal percentage (e.g. 10 or 7.5) discounted from the price
+	Discount string `bson:"discount,omitempty" json:"discount,omitempty"`
+
 	DateCreated time.Time `bson:"dateCreated,omitempty" json:"dateCreated,omitempty"`
 
 	DateUpdated time.Time `bson:"dateUpdated,omitempty" json:"dateUpdated,omitempty"`
+
+	//DeletedAt is the time the Project was moved to the trash. Zero while it is not deleted
+	DeletedAt time.Time `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+
+	//DeletedBy is the actor who moved the Project to the trash
+	DeletedBy string `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+}
+
+//Deleted checks if the Project is in the trash
+func (project *Project) Deleted() bool {
+	return !project.DeletedAt.IsZero()
+}
+
+//LastChanged returns the time of the last change known by the Project: its deletion, update or creation
+func (project *Project) LastChanged() time.Time {
+	lastChanged := project.DateCreated
+	for _, date := range []time.Time{project.DateUpdated, project.DeletedAt} {
+		if date.After(lastChanged) {
+			lastChanged = date
+		}
+	}
+	return lastChanged
+}
+
+//Undelete clears the deletion marker, e.g. of a Project received from a client, which cannot delete it by saving
+func (project *Project) Undelete() {
+	project.DeletedAt = time.Time{}
+	project.DeletedBy = ""
+}
+
+//FoldName returns the key of the name ignoring the case, the same for the names matched by strings.EqualFold (e.g. Ébène and ébène).
+//The SQL Repository indexes it, as the LOWER of SQLite folds only the ASCII letters
+func FoldName(name string) string {
+	return strings.Map(func(r rune) rune {
+		return unicode.ToLower(unicode.ToUpper(r))
+	}, name)
 }
 
 //Valid checks if the instance is in a valid state.
 //If the state is not valid, returns an domain.IdentifiableError (warning: it is important to return a domain.IdentifiableError following the example) availabe in the model_error.go file
 func (project *Project) Valid() (bool, error) {
 	if project == nil {
-		return false, ConstraintViolation("The Project is not instantiated")
+		return false, FieldViolation("project", ViolationRequired, "project")
 	}
+	violations := Violations{}
 	if strings.TrimSpace(project.Name) == "" {
-		return false, ConstraintViolation("The Project is invalid. The required attribute 'Name' is missing")
+		violations.Add("name", ViolationRequired, "name")
+	}
+	if project.UnitPrice.Validate("unitPrice", &violations) && !project.UnitPrice.IsPositive() {
+		violations.Add("unitPrice.amount", ViolationPositive, "unitPrice.amount")
 	}
-	if project.UnitPrice.IsZero() || project.UnitPrice.IsNegative() || strings.TrimSpace(project.UnitPrice.Currency) == "" {
-		return false, ConstraintViolation("The Project is invalid. The 'Unit Price' must be in a valid Currency and must be greater than zero")
+	if !ValidTimeUnit(project.TimeUnit) {
+		violations.Add("timeUnit", ViolationOneOf, "timeUnit", strings.Join(TimeUnits, ", "))
 	}
-	if project.TimeUnit != "Hour" &&
-		project.TimeUnit != "Day" &&
-		project.TimeUnit != "Week" &&
-		project.TimeUnit != "Month" {
-		return false, ConstraintViolation("The Project is invalid. The 'TimeUnit' must be any of [Hour, Day, Week, Month]")
+	project.validatePricing(&violations)
+	if err := violations.Err("project.invalid"); err != nil {
+		return false, err
 	}
 	return true, nil
 }
 
-//ProjectRepository is the specification of the features delivered by a Repository for a Project
+//ProjectRepository is the specification of the features delivered by a Repository for a Project.
+//The deleted Projects are kept in the trash, ignored by every method but GetTrash, Restore and Purge
 type ProjectRepository interface {
 	appcontext.Component
-	GetAll(lastProjectID string, pageSize int64) ([]*Project, error)
-	Get(id string) (*Project, error)
-	Save(project *Project) (*Project, error)
-	Update(project *Project) (*Project, error)
-	Delete(id string) error
+	GetAll(ctx context.Context, lastProjectID string, pageSize int64) ([]*Project, error)
+	Get(ctx context.Context, id string) (*Project, error)
+	Save(ctx context.Context, project *Project) (*Project, error)
+	Update(ctx context.Context, project *Project) (*Project, error)
+	//Delete moves the Project to the trash, marked as deleted by the actor
+	Delete(ctx context.Context, id string, deletedBy string) error
+	//GetTrash lists the deleted Projects ordered by ID, starting after the lastProjectID
+	GetTrash(ctx context.Context, lastProjectID string, pageSize int64) ([]*Project, error)
+	//Restore takes the deleted Project back from the trash
+	Restore(ctx context.Context, id string) (*Project, error)
+	//Purge permanently removes the Projects deleted before the date, returning how many were removed
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 
 type ProjectCreateUsecase interface {
-	Execute(project *Project) (*Project, error)
+	Execute(ctx context.Context, project *Project) (*Project, error)
 }
 
 type ProjectGetAllUsecase interface {
-	Execute(lastProjectID string, pageSize int64) ([]*Project, error)
+	Execute(ctx context.Context, lastProjectID string, pageSize int64) ([]*Project, error)
 }
 
 type ProjectGetByIDUsecase interface {
-	Execute(ID string) (*Project, error)
+	Execute(ctx context.Context, ID string) (*Project, error)
 }
 
 type ProjectUpdateUsecase interface {
-	Execute(project *Project) error
+	Execute(ctx context.Context, project *Project) error
 }
 
 type ProjectDeleteUsecase interface {
-	Execute(ID string) error
+	Execute(ctx context.Context, ID string) error
+}
+
+type ProjectGetTrashUsecase interface {
+	Execute(ctx context.Context, lastProjectID string, pageSize int64) ([]*Project, error)
+}
+
+type ProjectRestoreUsecase interface {
+	Execute(ctx context.Context, ID string) (*Project, error)
+}
+
+type ProjectPurgeUsecase interface {
+	Execute(ctx context.Context) (int64, error)
 }
 
 //GetProjectRepository gets the ProjectRepository current implementation
@@ -109,3 +175,32 @@ func GetProjectUpdateUsecase() ProjectUpdateUsecase {
 func GetProjectDeleteUsecase() ProjectDeleteUsecase {
 	return appcontext.Current.Get(appcontext.ProjectDeleteUsecase).(ProjectDeleteUsecase)
 }
+
+//GetProjectGetTrashUsecase gets the ProjectGetTrashUsecase current implementation
+func GetProjectGetTrashUsecase() ProjectGetTrashUsecase {
+	return appcontext.Current.Get(appcontext.ProjectGetTrashUsecase).(ProjectGetTrashUsecase)
+}
+
+//GetProjectRestoreUsecase gets the ProjectRestoreUsecase current implementation
+func GetProjectRestoreUsecase() ProjectRestoreUsecase {
+	return appcontext.Current.Get(appcontext.ProjectRestoreUsecase).(ProjectRestoreUsecase)
+}
+
+//GetProjectPurgeUsecase gets the ProjectPurgeUsecase current implementation
+func GetProjectPurgeUsecase() ProjectPurgeUsecase {
+	return appcontext.Current.Get(appcontext.ProjectPurgeUsecase).(ProjectPurgeUsecase)
+}
+
+//Localized returns a copy of the Project with the amounts displayed following the conventions of the Locale
+func (project Project) Localized(locale *Locale) Project {
+	project.UnitPrice = project.UnitPrice.Localized(locale)
+	if project.PricingTiers != nil {
+		tiers := make([]PricingTier, len(project.PricingTiers))
+		for index, tier := range project.PricingTiers {
+			tier.UnitPrice = tier.UnitPrice.Localized(locale)
+			tiers[index] = tier
+		}
+		project.PricingTiers = tiers
+	}
+	return project
+}
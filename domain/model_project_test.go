@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFoldName(t *testing.T) {
+	for _, names := range [][2]string{{"Project API", "PROJECT api"}, {"Ébène", "éBÈNE"}, {"Straße", "STRAẞE"}, {"Σοφία", "σοφία"}, {"ΟΔΟΣ", "οδος"}} {
+		assert.True(t, strings.EqualFold(names[0], names[1]), names)
+		assert.Equal(t, FoldName(names[0]), FoldName(names[1]), names)
+	}
+	assert.NotEqual(t, FoldName("Ébène"), FoldName("Ebene"))
+}
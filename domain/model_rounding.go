@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+//RoundingMode decides how an exact amount is rounded to the minor units of the currency
+type RoundingMode string
+
+//List of the supported RoundingModes
+const (
+	//RoundHalfEven rounds to the nearest neighbour, or to the even one when both are equidistant (banker's rounding)
+	RoundHalfEven RoundingMode = "halfEven"
+	//RoundHalfUp rounds to the nearest neighbour, or away from zero when both are equidistant
+	RoundHalfUp RoundingMode = "halfUp"
+	//RoundDown rounds towards zero (truncation)
+	RoundDown RoundingMode = "down"
+	//RoundUp rounds away from zero
+	RoundUp RoundingMode = "up"
+)
+
+//roundingModes are the supported RoundingModes, in the order they are listed in the violations
+var roundingModes = []RoundingMode{RoundHalfEven, RoundHalfUp, RoundDown, RoundUp}
+
+//cashIncrements are the smallest amounts, in minor units, paid in cash in the currencies without the smaller coins
+var cashIncrements = map[string]int64{
+	"AUD": 5,
+	"CAD": 5,
+	"CHF": 5,
+	"DKK": 50,
+	"NOK": 100,
+	"NZD": 10,
+	"SEK": 100,
+}
+
+//ParseRoundingMode returns the RoundingMode of the name (e.g. halfEven), ignoring the case
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	for _, mode := range roundingModes {
+		if strings.EqualFold(string(mode), strings.TrimSpace(name)) {
+			return mode, nil
+		}
+	}
+	return "", roundingModeViolation()
+}
+
+//roundingModeViolation reports a RoundingMode which is not any of the supported ones
+func roundingModeViolation() ConstraintViolationError {
+	names := make([]string, len(roundingModes))
+	for index, mode := range roundingModes {
+		names[index] = string(mode)
+	}
+	return FieldViolation("roundingMode", ViolationOneOf, "roundingMode", strings.Join(names, ", "))
+}
+
+//ParseDecimal reads an exact decimal number (e.g. 7.5 or -12.125)
+func ParseDecimal(decimal string) (*big.Rat, error) {
+	if !decimalPattern.MatchString(strings.TrimSpace(decimal)) {
+		return nil, FieldViolation("decimal", ViolationFormat, "decimal", decimal)
+	}
+	value, _ := new(big.Rat).SetString(strings.TrimSpace(decimal))
+	return value, nil
+}
+
+//FormatDecimal writes the exact value as a decimal number without trailing zeros, rounded to 6 decimal places if it has more (e.g. 4.333333)
+func FormatDecimal(value *big.Rat) string {
+	decimal := value.FloatString(6)
+	decimal = strings.TrimRight(strings.TrimRight(decimal, "0"), ".")
+	if decimal == "-0" {
+		return "0"
+	}
+	return decimal
+}
+
+//round the exact value to an integer following the mode
+func round(value *big.Rat, mode RoundingMode) (*big.Int, error) {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient, nil
+	}
+	awayFromZero := big.NewInt(int64(value.Sign()))
+	//The remainder is compared to the half of the denominator as 2*|remainder| against the denominator
+	doubledRemainder := new(big.Int).Abs(remainder)
+	half := doubledRemainder.Lsh(doubledRemainder, 1).Cmp(value.Denom())
+	switch mode {
+	case RoundDown:
+	case RoundUp:
+		quotient.Add(quotient, awayFromZero)
+	case RoundHalfUp:
+		if half >= 0 {
+			quotient.Add(quotient, awayFromZero)
+		}
+	case RoundHalfEven:
+		if half > 0 || (half == 0 && quotient.Bit(0) == 1) {
+			quotient.Add(quotient, awayFromZero)
+		}
+	default:
+		return nil, roundingModeViolation()
+	}
+	return quotient, nil
+}
+
+//roundToInt64 rounds the exact value to an amount in minor units
+func roundToInt64(value *big.Rat, mode RoundingMode) (int64, error) {
+	rounded, err := round(value, mode)
+	if err != nil {
+		return 0, err
+	}
+	if !rounded.IsInt64() {
+		return 0, FieldViolation("amount", ViolationRange, "amount", strconv.FormatInt(math.MinInt64, 10), new(big.Int).Add(big.NewInt(math.MaxInt64), big.NewInt(1)).String())
+	}
+	return rounded.Int64(), nil
+}
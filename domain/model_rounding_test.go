@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//modes lists the RoundingModes in the order of the expected amounts of the tests
+var modes = []RoundingMode{RoundHalfEven, RoundHalfUp, RoundDown, RoundUp}
+
+func mustParseDecimal(t *testing.T, decimal string) *big.Rat {
+	value, err := ParseDecimal(decimal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected [4]int64
+	}{
+		{value: "2", expected: [4]int64{2, 2, 2, 2}},
+		{value: "2.4", expected: [4]int64{2, 2, 2, 3}},
+		{value: "2.5", expected: [4]int64{2, 3, 2, 3}},
+		{value: "3.5", expected: [4]int64{4, 4, 3, 4}},
+		{value: "2.6", expected: [4]int64{3, 3, 2, 3}},
+		{value: "-2.4", expected: [4]int64{-2, -2, -2, -3}},
+		{value: "-2.5", expected: [4]int64{-2, -3, -2, -3}},
+		{value: "-3.5", expected: [4]int64{-4, -4, -3, -4}},
+		{value: "0.0001", expected: [4]int64{0, 0, 0, 1}},
+	}
+	for _, tt := range tests {
+		for index, mode := range modes {
+			t.Run(tt.value+" "+string(mode), func(t *testing.T) {
+				rounded, err := roundToInt64(mustParseDecimal(t, tt.value), mode)
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.expected[index], rounded)
+				}
+			})
+		}
+	}
+}
+
+func TestMoneyMultiplyBy(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		factor   string
+		expected [4]int64
+	}{
+		{name: "Exponent 0 half", money: Money{Amount: 1001, Currency: "JPY"}, factor: "0.5", expected: [4]int64{500, 501, 500, 501}},
+		{name: "Exponent 0 odd half", money: Money{Amount: 1003, Currency: "JPY"}, factor: "0.5", expected: [4]int64{502, 502, 501, 502}},
+		{name: "Exponent 2 hours", money: Money{Amount: 1001, Currency: "EUR"}, factor: "7.5", expected: [4]int64{7508, 7508, 7507, 7508}},
+		{name: "Exponent 2 exact", money: Money{Amount: 1000, Currency: "EUR"}, factor: "7.5", expected: [4]int64{7500, 7500, 7500, 7500}},
+		{name: "Exponent 2 negative", money: Money{Amount: -1001, Currency: "EUR"}, factor: "0.5", expected: [4]int64{-500, -501, -500, -501}},
+		{name: "Exponent 3 half", money: Money{Amount: 1001, Currency: "KWD"}, factor: "2.5", expected: [4]int64{2502, 2503, 2502, 2503}},
+		{name: "Exponent 3 below half", money: Money{Amount: 1234, Currency: "BHD"}, factor: "1.001", expected: [4]int64{1235, 1235, 1235, 1236}},
+		{name: "Exponent 4 quarter", money: Money{Amount: 10001, Currency: "CLF"}, factor: "0.25", expected: [4]int64{2500, 2500, 2500, 2501}},
+		{name: "Exponent 4 three quarters", money: Money{Amount: 10003, Currency: "CLF"}, factor: "0.25", expected: [4]int64{2501, 2501, 2500, 2501}},
+	}
+	for _, tt := range tests {
+		for index, mode := range modes {
+			t.Run(tt.name+" "+string(mode), func(t *testing.T) {
+				result, err := tt.money.MultiplyBy(mustParseDecimal(t, tt.factor), mode)
+				if assert.NoError(t, err) {
+					assert.Equal(t, Money{Amount: tt.expected[index], Currency: tt.money.Currency}, *result)
+				}
+			})
+		}
+	}
+}
+
+func TestMoneyDivideBy(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		divisor  string
+		expected [4]int64
+	}{
+		{name: "Exponent 0 exact", money: Money{Amount: 1000, Currency: "JPY"}, divisor: "8", expected: [4]int64{125, 125, 125, 125}},
+		{name: "Exponent 0 half", money: Money{Amount: 5, Currency: "JPY"}, divisor: "2", expected: [4]int64{2, 3, 2, 3}},
+		{name: "Exponent 2 thirds", money: Money{Amount: 1000, Currency: "EUR"}, divisor: "3", expected: [4]int64{333, 333, 333, 334}},
+		{name: "Exponent 2 days", money: Money{Amount: 100000, Currency: "USD"}, divisor: "7.5", expected: [4]int64{13333, 13333, 13333, 13334}},
+		{name: "Exponent 3 half", money: Money{Amount: 1, Currency: "KWD"}, divisor: "2", expected: [4]int64{0, 1, 0, 1}},
+		{name: "Exponent 4 negative", money: Money{Amount: -10000, Currency: "CLF"}, divisor: "6", expected: [4]int64{-1667, -1667, -1666, -1667}},
+	}
+	for _, tt := range tests {
+		for index, mode := range modes {
+			t.Run(tt.name+" "+string(mode), func(t *testing.T) {
+				result, err := tt.money.DivideBy(mustParseDecimal(t, tt.divisor), mode)
+				if assert.NoError(t, err) {
+					assert.Equal(t, Money{Amount: tt.expected[index], Currency: tt.money.Currency}, *result)
+				}
+			})
+		}
+	}
+}
+
+func TestMoneyDivideByZero(t *testing.T) {
+	_, err := (&Money{Amount: 1000, Currency: "EUR"}).DivideBy(new(big.Rat), RoundHalfEven)
+	if violation, ok := err.(ConstraintViolationError); assert.True(t, ok) && assert.Len(t, violation.Violations, 1) {
+		assert.Equal(t, Violation{Field: "divisor", Code: ViolationNonZero, Message: "The 'divisor' must not be zero", Args: []interface{}{"divisor"}}, violation.Violations[0])
+		assert.Equal(t, "O 'divisor' não pode ser zero", violation.Violations[0].Localize(Portuguese).Message)
+	}
+}
+
+func TestMoneyPercentage(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		percent  string
+		expected [4]int64
+	}{
+		{name: "Exponent 0", money: Money{Amount: 999, Currency: "JPY"}, percent: "12.5", expected: [4]int64{125, 125, 124, 125}},
+		{name: "Exponent 2 exact", money: Money{Amount: 10000, Currency: "EUR"}, percent: "12.5", expected: [4]int64{1250, 1250, 1250, 1250}},
+		{name: "Exponent 2 fraction", money: Money{Amount: 99, Currency: "EUR"}, percent: "12.5", expected: [4]int64{12, 12, 12, 13}},
+		{name: "Exponent 2 half", money: Money{Amount: 50, Currency: "USD"}, percent: "5", expected: [4]int64{2, 3, 2, 3}},
+		{name: "Exponent 3", money: Money{Amount: 1234, Currency: "KWD"}, percent: "15", expected: [4]int64{185, 185, 185, 186}},
+		{name: "Exponent 4", money: Money{Amount: 12345, Currency: "CLF"}, percent: "0.1", expected: [4]int64{12, 12, 12, 13}},
+		{name: "More than a hundred", money: Money{Amount: 1000, Currency: "EUR"}, percent: "150", expected: [4]int64{1500, 1500, 1500, 1500}},
+	}
+	for _, tt := range tests {
+		for index, mode := range modes {
+			t.Run(tt.name+" "+string(mode), func(t *testing.T) {
+				result, err := tt.money.Percentage(mustParseDecimal(t, tt.percent), mode)
+				if assert.NoError(t, err) {
+					assert.Equal(t, Money{Amount: tt.expected[index], Currency: tt.money.Currency}, *result)
+				}
+			})
+		}
+	}
+}
+
+func TestMoneyRoundCash(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		expected [4]int64
+	}{
+		{name: "CHF below half", money: Money{Amount: 102, Currency: "CHF"}, expected: [4]int64{100, 100, 100, 105}},
+		{name: "CHF above half", money: Money{Amount: 103, Currency: "CHF"}, expected: [4]int64{105, 105, 100, 105}},
+		{name: "CHF multiple", money: Money{Amount: 1005, Currency: "CHF"}, expected: [4]int64{1005, 1005, 1005, 1005}},
+		{name: "CHF negative", money: Money{Amount: -103, Currency: "CHF"}, expected: [4]int64{-105, -105, -100, -105}},
+		{name: "NOK half to even", money: Money{Amount: 250, Currency: "NOK"}, expected: [4]int64{200, 300, 200, 300}},
+		{name: "NOK half to odd", money: Money{Amount: 150, Currency: "NOK"}, expected: [4]int64{200, 200, 100, 200}},
+		{name: "Without cash rounding", money: Money{Amount: 103, Currency: "EUR"}, expected: [4]int64{103, 103, 103, 103}},
+		{name: "Exponent 0 without cash rounding", money: Money{Amount: 103, Currency: "JPY"}, expected: [4]int64{103, 103, 103, 103}},
+	}
+	for _, tt := range tests {
+		for index, mode := range modes {
+			t.Run(tt.name+" "+string(mode), func(t *testing.T) {
+				result, err := tt.money.RoundCash(mode)
+				if assert.NoError(t, err) {
+					assert.Equal(t, Money{Amount: tt.expected[index], Currency: tt.money.Currency}, *result)
+				}
+			})
+		}
+	}
+}
+
+func TestMoneyOutOfRange(t *testing.T) {
+	_, err := (&Money{Amount: 1 << 62, Currency: "EUR"}).MultiplyBy(big.NewRat(4, 1), RoundHalfEven)
+	assert.IsType(t, ConstraintViolationError{}, err)
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	mode, err := ParseRoundingMode("HalfEven")
+	if assert.NoError(t, err) {
+		assert.Equal(t, RoundHalfEven, mode)
+	}
+	_, err = ParseRoundingMode("ceiling")
+	if violation, ok := err.(ConstraintViolationError); assert.True(t, ok) && assert.Len(t, violation.Violations, 1) {
+		assert.Equal(t, "roundingMode", violation.Violations[0].Field)
+		assert.Equal(t, "The 'roundingMode' must be any of [halfEven, halfUp, down, up]", violation.Violations[0].Message)
+	}
+}
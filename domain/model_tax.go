@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+)
+
+//TaxRate is the rate of a tax (e.g. VAT) charged in a jurisdiction for a category of service, effective in a period
+type TaxRate struct {
+	//Jurisdiction is the ISO 3166 code of the country (e.g. PT) or of its subdivision (e.g. CA-QC). The rates of a country also apply to its subdivisions
+	Jurisdiction string `bson:"jurisdiction" json:"jurisdiction"`
+
+	//Category of the taxed service (e.g. consulting)
+	Category string `bson:"category" json:"category"`
+
+	//Name of the tax shown in the tax lines (e.g. VAT)
+	Name string `bson:"name" json:"name"`
+
+	//Rate is the decimal percentage of the tax (e.g. 23 or 9.975)
+	Rate string `bson:"rate" json:"rate"`
+
+	EffectiveFrom time.Time `bson:"effectiveFrom" json:"effectiveFrom"`
+
+	//EffectiveUntil is the first moment the rate is not effective anymore. Zero while it is effective
+	EffectiveUntil time.Time `bson:"effectiveUntil,omitempty" json:"effectiveUntil,omitempty"`
+}
+
+//EffectiveAt checks if the rate is effective at the date
+func (taxRate *TaxRate) EffectiveAt(date time.Time) bool {
+	return !date.Before(taxRate.EffectiveFrom) && (taxRate.EffectiveUntil.IsZero() || date.Before(taxRate.EffectiveUntil))
+}
+
+//AppliesTo checks if the rate is charged in the jurisdiction for the category at the date
+func (taxRate *TaxRate) AppliesTo(jurisdiction string, category string, date time.Time) bool {
+	for _, applicableJurisdiction := range Jurisdictions(jurisdiction) {
+		if strings.EqualFold(taxRate.Jurisdiction, applicableJurisdiction) {
+			return strings.EqualFold(taxRate.Category, category) && taxRate.EffectiveAt(date)
+		}
+	}
+	return false
+}
+
+//Percent returns the exact rate
+func (taxRate *TaxRate) Percent() (*big.Rat, error) {
+	return ParseDecimal(taxRate.Rate)
+}
+
+//Valid checks if the instance is in a valid state
+func (taxRate *TaxRate) Valid() (bool, error) {
+	violations := Violations{}
+	if strings.TrimSpace(taxRate.Jurisdiction) == "" {
+		violations.Add("jurisdiction", ViolationRequired, "jurisdiction")
+	}
+	if strings.TrimSpace(taxRate.Category) == "" {
+		violations.Add("category", ViolationRequired, "category")
+	}
+	if strings.TrimSpace(taxRate.Name) == "" {
+		violations.Add("name", ViolationRequired, "name")
+	}
+	if percent, err := taxRate.Percent(); err != nil || percent.Sign() < 0 {
+		violations.Add("rate", ViolationFormat, "rate", taxRate.Rate)
+	}
+	if taxRate.EffectiveFrom.IsZero() {
+		violations.Add("effectiveFrom", ViolationRequired, "effectiveFrom")
+	}
+	if !taxRate.EffectiveUntil.IsZero() && !taxRate.EffectiveUntil.After(taxRate.EffectiveFrom) {
+		violations.Add("effectiveUntil", ViolationFormat, "effectiveUntil", taxRate.EffectiveUntil.Format(time.RFC3339))
+	}
+	if err := violations.Err("taxRate.invalid"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//Jurisdictions lists the jurisdiction and the country it belongs to (e.g. CA-QC and CA), which rates are charged together
+func Jurisdictions(jurisdiction string) []string {
+	jurisdiction = strings.ToUpper(strings.TrimSpace(jurisdiction))
+	if index := strings.Index(jurisdiction, "-"); index > 0 {
+		return []string{jurisdiction, jurisdiction[:index]}
+	}
+	return []string{jurisdiction}
+}
+
+//TaxQuery describes the price which taxes are calculated
+type TaxQuery struct {
+	Price Money `json:"price"`
+
+	Jurisdiction string `json:"jurisdiction"`
+
+	Category string `json:"category"`
+
+	//Date in which the taxes are charged. Defaults to the current time
+	Date time.Time `json:"date,omitempty"`
+
+	//TaxInclusive informs that the Price already includes the taxes, instead of being the net amount
+	TaxInclusive bool `json:"taxInclusive"`
+}
+
+//TaxLine is the amount charged by a tax
+type TaxLine struct {
+	Name string `json:"name"`
+
+	Jurisdiction string `json:"jurisdiction"`
+
+	Rate string `json:"rate"`
+
+	Amount Money `json:"amount"`
+}
+
+//TaxBreakdown is a price split in its net amount and its taxes
+type TaxBreakdown struct {
+	Net Money `json:"net"`
+
+	Taxes []TaxLine `json:"taxes"`
+
+	Gross Money `json:"gross"`
+}
+
+//CalculateTaxes splits the price (the net amount or, if taxInclusive, the gross amount) in its net amount and the taxes of the rates.
+//The total tax is rounded once, following the mode, and allocated to the tax lines proportionally to their rates, without losing cents
+func CalculateTaxes(price Money, rates []TaxRate, taxInclusive bool, mode RoundingMode) (*TaxBreakdown, error) {
+	percents := make([]*big.Rat, len(rates))
+	totalPercent := new(big.Rat)
+	for index := range rates {
+		percent, err := rates[index].Percent()
+		if err != nil {
+			return nil, InternalError(fmt.Sprintf("Invalid rate of the tax %s in %s: %s", rates[index].Name, rates[index].Jurisdiction, rates[index].Rate))
+		}
+		percents[index] = percent
+		totalPercent.Add(totalPercent, percent)
+	}
+
+	breakdown := &TaxBreakdown{}
+	if taxInclusive {
+		net, err := price.DivideBy(new(big.Rat).Add(big.NewRat(1, 1), new(big.Rat).Quo(totalPercent, big.NewRat(100, 1))), mode)
+		if err != nil {
+			return nil, err
+		}
+		breakdown.Net = *net
+		breakdown.Gross = Money{Amount: price.Amount, Currency: price.Currency}
+	} else {
+		tax, err := price.Percentage(totalPercent, mode)
+		if err != nil {
+			return nil, err
+		}
+		breakdown.Net = Money{Amount: price.Amount, Currency: price.Currency}
+		breakdown.Gross = Money{Amount: price.Amount + tax.Amount, Currency: price.Currency}
+	}
+
+	taxAmounts, err := allocateTax(Money{Amount: breakdown.Gross.Amount - breakdown.Net.Amount, Currency: price.Currency}, percents)
+	if err != nil {
+		return nil, err
+	}
+	breakdown.Taxes = make([]TaxLine, len(rates))
+	for index, rate := range rates {
+		breakdown.Taxes[index] = TaxLine{Name: rate.Name, Jurisdiction: rate.Jurisdiction, Rate: rate.Rate, Amount: taxAmounts[index]}
+	}
+	return breakdown, nil
+}
+
+//allocateTax splits the total tax in exact shares proportional to the percents, truncated to the minor units. The units left are
+//distributed one by one to the first tax lines, as Money.Allocate does, without losing cents
+func allocateTax(tax Money, percents []*big.Rat) ([]Money, error) {
+	taxAmounts := make([]Money, len(percents))
+	for index := range taxAmounts {
+		taxAmounts[index] = Money{Currency: tax.Currency}
+	}
+	if tax.Amount == 0 || len(percents) == 0 {
+		return taxAmounts, nil
+	}
+
+	totalPercent := new(big.Rat)
+	for _, percent := range percents {
+		totalPercent.Add(totalPercent, percent)
+	}
+	if totalPercent.Sign() <= 0 {
+		return nil, InternalError(fmt.Sprintf("Could not allocate the tax %s to tax lines without a positive rate", tax.Decimal()))
+	}
+	allocated := int64(0)
+	for index, percent := range percents {
+		share := new(big.Rat).Mul(new(big.Rat).SetInt64(tax.Amount), new(big.Rat).Quo(percent, totalPercent))
+		//The rates are not negative, so each share is at most the tax
+		amount := new(big.Int).Quo(share.Num(), share.Denom())
+		if !amount.IsInt64() {
+			return nil, InternalError(fmt.Sprintf("Could not allocate the tax %s to the rate %s", tax.Decimal(), percent.FloatString(10)))
+		}
+		taxAmounts[index].Amount = amount.Int64()
+		allocated += amount.Int64()
+	}
+	unit := int64(1)
+	if tax.Amount < 0 {
+		unit = -1
+	}
+	for index := 0; allocated != tax.Amount; index = (index + 1) % len(taxAmounts) {
+		taxAmounts[index].Amount += unit
+		allocated += unit
+	}
+	return taxAmounts, nil
+}
+
+//TaxRateRepository is the specification of the features delivered by a Repository for the TaxRates
+type TaxRateRepository interface {
+	appcontext.Component
+	//Find the rates charged in the jurisdiction (including the ones of its country) for the category at the date
+	Find(ctx context.Context, jurisdiction string, category string, date time.Time) ([]TaxRate, error)
+}
+
+type TaxCalculateUsecase interface {
+	Execute(ctx context.Context, query TaxQuery) (*TaxBreakdown, error)
+}
+
+//GetTaxRateRepository gets the TaxRateRepository current implementation
+func GetTaxRateRepository() TaxRateRepository {
+	return appcontext.Current.Get(appcontext.TaxRateRepository).(TaxRateRepository)
+}
+
+//GetTaxCalculateUsecase gets the TaxCalculateUsecase current implementation
+func GetTaxCalculateUsecase() TaxCalculateUsecase {
+	return appcontext.Current.Get(appcontext.TaxCalculateUsecase).(TaxCalculateUsecase)
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	taxYearStart = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	taxYearEnd   = time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestCalculateTaxes(t *testing.T) {
+	vat := TaxRate{Jurisdiction: "PT", Category: "consulting", Name: "VAT", Rate: "23", EffectiveFrom: taxYearStart}
+	gst := TaxRate{Jurisdiction: "CA", Category: "consulting", Name: "GST", Rate: "5", EffectiveFrom: taxYearStart}
+	qst := TaxRate{Jurisdiction: "CA-QC", Category: "consulting", Name: "QST", Rate: "9.975", EffectiveFrom: taxYearStart}
+	consumptionTax := TaxRate{Jurisdiction: "JP", Category: "consulting", Name: "Consumption Tax", Rate: "10", EffectiveFrom: taxYearStart}
+	//The denominators of 2.5 (5/2) and 1.2 (6/5) do not divide each other
+	reduced := TaxRate{Jurisdiction: "XX", Category: "consulting", Name: "Reduced", Rate: "2.5", EffectiveFrom: taxYearStart}
+	surcharge := TaxRate{Jurisdiction: "XX", Category: "consulting", Name: "Surcharge", Rate: "1.2", EffectiveFrom: taxYearStart}
+	exempt := TaxRate{Jurisdiction: "US", Category: "consulting", Name: "Sales Tax", Rate: "0", EffectiveFrom: taxYearStart}
+	tests := []struct {
+		name          string
+		price         Money
+		rates         []TaxRate
+		taxInclusive  bool
+		expectedNet   int64
+		expectedTaxes []int64
+		expectedGross int64
+	}{
+		{name: "Exclusive", price: Money{Amount: 10000, Currency: "EUR"}, rates: []TaxRate{vat}, expectedNet: 10000, expectedTaxes: []int64{2300}, expectedGross: 12300},
+		{name: "Inclusive", price: Money{Amount: 12300, Currency: "EUR"}, rates: []TaxRate{vat}, taxInclusive: true, expectedNet: 10000, expectedTaxes: []int64{2300}, expectedGross: 12300},
+		{name: "Inclusive rounded", price: Money{Amount: 10000, Currency: "EUR"}, rates: []TaxRate{vat}, taxInclusive: true, expectedNet: 8130, expectedTaxes: []int64{1870}, expectedGross: 10000},
+		{name: "Several rates", price: Money{Amount: 10000, Currency: "CAD"}, rates: []TaxRate{gst, qst}, expectedNet: 10000, expectedTaxes: []int64{501, 997}, expectedGross: 11498},
+		{name: "Several rates inclusive", price: Money{Amount: 11498, Currency: "CAD"}, rates: []TaxRate{gst, qst}, taxInclusive: true, expectedNet: 10000, expectedTaxes: []int64{501, 997}, expectedGross: 11498},
+		{name: "Exponent 0", price: Money{Amount: 1100, Currency: "JPY"}, rates: []TaxRate{consumptionTax}, taxInclusive: true, expectedNet: 1000, expectedTaxes: []int64{100}, expectedGross: 1100},
+		{name: "Coprime denominators", price: Money{Amount: 1000000, Currency: "EUR"}, rates: []TaxRate{reduced, surcharge}, expectedNet: 1000000, expectedTaxes: []int64{25000, 12000}, expectedGross: 1037000},
+		{name: "Coprime denominators inclusive", price: Money{Amount: 1037000, Currency: "EUR"}, rates: []TaxRate{reduced, surcharge}, taxInclusive: true, expectedNet: 1000000, expectedTaxes: []int64{25000, 12000}, expectedGross: 1037000},
+		{name: "Leftover cents", price: Money{Amount: 101, Currency: "EUR"}, rates: []TaxRate{reduced, surcharge, reduced}, expectedNet: 101, expectedTaxes: []int64{3, 1, 2}, expectedGross: 107},
+		{name: "Zero rate", price: Money{Amount: 10000, Currency: "USD"}, rates: []TaxRate{exempt}, expectedNet: 10000, expectedTaxes: []int64{0}, expectedGross: 10000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown, err := CalculateTaxes(tt.price, tt.rates, tt.taxInclusive, RoundHalfEven)
+			if assert.NoError(t, err) {
+				assert.Equal(t, Money{Amount: tt.expectedNet, Currency: tt.price.Currency}, breakdown.Net)
+				assert.Equal(t, Money{Amount: tt.expectedGross, Currency: tt.price.Currency}, breakdown.Gross)
+				totalTax := int64(0)
+				if assert.Len(t, breakdown.Taxes, len(tt.expectedTaxes)) {
+					for index, taxLine := range breakdown.Taxes {
+						assert.Equal(t, tt.rates[index].Name, taxLine.Name)
+						assert.Equal(t, Money{Amount: tt.expectedTaxes[index], Currency: tt.price.Currency}, taxLine.Amount)
+						totalTax += taxLine.Amount.Amount
+					}
+				}
+				assert.Equal(t, breakdown.Gross.Amount-breakdown.Net.Amount, totalTax)
+			}
+		})
+	}
+}
+
+func TestTaxRateAppliesTo(t *testing.T) {
+	rate := TaxRate{Jurisdiction: "CA", Category: "consulting", Name: "GST", Rate: "5", EffectiveFrom: taxYearStart, EffectiveUntil: taxYearEnd}
+	tests := []struct {
+		name         string
+		jurisdiction string
+		category     string
+		date         time.Time
+		expected     bool
+	}{
+		{name: "Country", jurisdiction: "CA", category: "consulting", date: taxYearStart, expected: true},
+		{name: "Subdivision", jurisdiction: "ca-qc", category: "Consulting", date: taxYearStart.AddDate(0, 6, 0), expected: true},
+		{name: "Other country", jurisdiction: "US", category: "consulting", date: taxYearStart, expected: false},
+		{name: "Other category", jurisdiction: "CA", category: "training", date: taxYearStart, expected: false},
+		{name: "Before", jurisdiction: "CA", category: "consulting", date: taxYearStart.Add(-time.Second), expected: false},
+		{name: "Until", jurisdiction: "CA", category: "consulting", date: taxYearEnd, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, rate.AppliesTo(tt.jurisdiction, tt.category, tt.date))
+		})
+	}
+}
+
+func TestTaxRateValid(t *testing.T) {
+	valid, err := (&TaxRate{Jurisdiction: "PT", Category: "consulting", Name: "VAT", Rate: "23", EffectiveFrom: taxYearStart}).Valid()
+	assert.True(t, valid)
+	assert.NoError(t, err)
+
+	valid, err = (&TaxRate{Rate: "-1", EffectiveFrom: taxYearEnd, EffectiveUntil: taxYearStart}).Valid()
+	assert.False(t, valid)
+	if constraintViolation, ok := err.(ConstraintViolationError); assert.True(t, ok) {
+		fields := make([]string, 0)
+		for _, violation := range constraintViolation.Violations {
+			fields = append(fields, violation.Field)
+		}
+		assert.Equal(t, []string{"jurisdiction", "category", "name", "rate", "effectiveUntil"}, fields)
+	}
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+)
+
+//ProjectVersion is an immutable snapshot of a Project, numbered from 1 by each change recorded by the Repository in the same transaction
+type ProjectVersion struct {
+	Number int64 `bson:"number" json:"number"`
+
+	//DateCreated is the time of the change, from which the Project looked like this version until the next one
+	DateCreated time.Time `bson:"dateCreated" json:"dateCreated"`
+
+	//CreatedBy is the actor who made the change
+	CreatedBy string `bson:"createdBy" json:"createdBy"`
+
+	Project Project `bson:"project" json:"project"`
+}
+
+//NewProjectVersion creates the version number of the Project changed at the date by the actor of the context.
+//The version keeps its own copy of the pricing tiers
+func NewProjectVersion(ctx context.Context, number int64, project *Project, date time.Time) ProjectVersion {
+	snapshot := *project
+	snapshot.PricingTiers = append([]PricingTier(nil), project.PricingTiers...)
+	return ProjectVersion{
+		Number:      number,
+		DateCreated: date,
+		CreatedBy:   Actor(ctx),
+		Project:     snapshot,
+	}
+}
+
+//Localized returns a copy of the ProjectVersion with the amounts displayed following the conventions of the Locale
+func (version ProjectVersion) Localized(locale *Locale) ProjectVersion {
+	version.Project = version.Project.Localized(locale)
+	return version
+}
+
+//RevertTo copies the fields of the version sent by the clients to the Project, keeping its ID, dates and deletion marker
+func (project *Project) RevertTo(version *ProjectVersion) {
+	project.Name = version.Project.Name
+	project.UnitPrice = version.Project.UnitPrice
+	project.TimeUnit = version.Project.TimeUnit
+	project.PricingTiers = append([]PricingTier(nil), version.Project.PricingTiers...)
+	project.Discount = version.Project.Discount
+}
+
+//ProjectDiff lists the changed fields of a Project between two of its versions
+type ProjectDiff struct {
+	ProjectID string `json:"projectId"`
+
+	From int64 `json:"from"`
+
+	To int64 `json:"to"`
+
+	Changes []FieldChange `json:"changes"`
+}
+
+//ProjectVersionRepository is the specification of the features delivered by a Repository for the ProjectVersion.
+//The versions are written by the ProjectRepository and kept after the Project is purged
+type ProjectVersionRepository interface {
+	appcontext.Component
+	//Get the version number of the Project
+	Get(ctx context.Context, projectID string, number int64) (*ProjectVersion, error)
+	//GetAsOf gets the version of the Project current at the date, the last one created until then
+	GetAsOf(ctx context.Context, projectID string, asOf time.Time) (*ProjectVersion, error)
+}
+
+type ProjectGetVersionUsecase interface {
+	Execute(ctx context.Context, ID string, number int64) (*ProjectVersion, error)
+}
+
+type ProjectGetAsOfUsecase interface {
+	Execute(ctx context.Context, ID string, asOf time.Time) (*Project, error)
+}
+
+type ProjectDiffUsecase interface {
+	Execute(ctx context.Context, ID string, from int64, to int64) (*ProjectDiff, error)
+}
+
+type ProjectRevertUsecase interface {
+	Execute(ctx context.Context, ID string, number int64) (*Project, error)
+}
+
+//GetProjectVersionRepository gets the ProjectVersionRepository current implementation
+func GetProjectVersionRepository() ProjectVersionRepository {
+	return appcontext.Current.Get(appcontext.ProjectVersionRepository).(ProjectVersionRepository)
+}
+
+//GetProjectGetVersionUsecase gets the ProjectGetVersionUsecase current implementation
+func GetProjectGetVersionUsecase() ProjectGetVersionUsecase {
+	return appcontext.Current.Get(appcontext.ProjectGetVersionUsecase).(ProjectGetVersionUsecase)
+}
+
+//GetProjectGetAsOfUsecase gets the ProjectGetAsOfUsecase current implementation
+func GetProjectGetAsOfUsecase() ProjectGetAsOfUsecase {
+	return appcontext.Current.Get(appcontext.ProjectGetAsOfUsecase).(ProjectGetAsOfUsecase)
+}
+
+//GetProjectDiffUsecase gets the ProjectDiffUsecase current implementation
+func GetProjectDiffUsecase() ProjectDiffUsecase {
+	return appcontext.Current.Get(appcontext.ProjectDiffUsecase).(ProjectDiffUsecase)
+}
+
+//GetProjectRevertUsecase gets the ProjectRevertUsecase current implementation
+func GetProjectRevertUsecase() ProjectRevertUsecase {
+	return appcontext.Current.Get(appcontext.ProjectRevertUsecase).(ProjectRevertUsecase)
+}
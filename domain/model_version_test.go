@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNewProjectVersion(t *testing.T) {
+	project := Project{
+		ID:           primitive.NewObjectID(),
+		Name:         "Project API",
+		UnitPrice:    Money{Amount: 10000, Currency: "EUR"},
+		TimeUnit:     TimeUnitHour,
+		PricingTiers: []PricingTier{{From: "10", UnitPrice: Money{Amount: 9000, Currency: "EUR"}}},
+	}
+	date := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	version := NewProjectVersion(appcontext.WithActor(context.Background(), "alice"), 2, &project, date)
+	assert.Equal(t, int64(2), version.Number)
+	assert.Equal(t, date, version.DateCreated)
+	assert.Equal(t, "alice", version.CreatedBy)
+	assert.Equal(t, project, version.Project)
+
+	//The version is not changed by the later changes of the Project
+	project.PricingTiers[0].From = "20"
+	assert.Equal(t, "10", version.Project.PricingTiers[0].From)
+}
+
+func TestProjectRevertTo(t *testing.T) {
+	deletedAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	project := Project{ID: primitive.NewObjectID(), Name: "Project API v2", UnitPrice: Money{Amount: 12000, Currency: "EUR"}, TimeUnit: TimeUnitDay, Discount: "5", DateCreated: deletedAt}
+	version := ProjectVersion{Number: 1, Project: Project{
+		ID:           primitive.NewObjectID(),
+		Name:         "Project API",
+		UnitPrice:    Money{Amount: 10000, Currency: "EUR"},
+		TimeUnit:     TimeUnitHour,
+		PricingTiers: []PricingTier{{From: "10", UnitPrice: Money{Amount: 9000, Currency: "EUR"}}},
+		DeletedAt:    deletedAt,
+		DeletedBy:    "alice",
+	}}
+
+	reverted := project
+	reverted.RevertTo(&version)
+	assert.Equal(t, project.ID, reverted.ID)
+	assert.Equal(t, project.DateCreated, reverted.DateCreated)
+	assert.False(t, reverted.Deleted())
+	assert.Equal(t, []FieldChange{
+		{Field: "discount", Before: "5"},
+		{Field: "name", Before: "Project API v2", After: "Project API"},
+		{Field: "pricingTiers[0].from", After: "10"},
+		{Field: "pricingTiers[0].unitPrice.amount", After: "90.00"},
+		{Field: "pricingTiers[0].unitPrice.currency", After: "EUR"},
+		{Field: "timeUnit", Before: TimeUnitDay, After: TimeUnitHour},
+		{Field: "unitPrice.amount", Before: "120.00", After: "100.00"},
+	}, DiffProjects(&project, &reverted))
+}
+
+func TestProjectLastChanged(t *testing.T) {
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	project := Project{DateCreated: created}
+	assert.Equal(t, created, project.LastChanged())
+	project.DateUpdated = created.Add(time.Hour)
+	assert.Equal(t, created.Add(time.Hour), project.LastChanged())
+	project.DeletedAt = created.Add(2 * time.Hour)
+	assert.Equal(t, created.Add(2*time.Hour), project.LastChanged())
+}
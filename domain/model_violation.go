@@ -0,0 +1,75 @@
+package domain
+
+//List of consts containing the machine-readable codes of the Violations
+const (
+	//ViolationRequired - the field is missing or blank
+	ViolationRequired = "required"
+	//ViolationPositive - the number must be greater than zero
+	ViolationPositive = "positive"
+	//ViolationOneOf - the value is not any of the allowed ones
+	ViolationOneOf = "oneOf"
+	//ViolationCurrency - the value is not an ISO 4217 currency code
+	ViolationCurrency = "currency"
+	//ViolationPrecision - the amount has more fraction digits than its currency allows
+	ViolationPrecision = "precision"
+	//ViolationFormat - the value does not follow the expected format (e.g. an id or a number)
+	ViolationFormat = "format"
+	//ViolationType - the JSON value has another type than the field (e.g. a string for a number)
+	ViolationType = "type"
+	//ViolationSyntax - the request body is not a valid JSON
+	ViolationSyntax = "syntax"
+	//ViolationMismatch - the value differs from another one which must be equal (e.g. the id in the path and in the body)
+	ViolationMismatch = "mismatch"
+	//ViolationRange - the number is out of the allowed range
+	ViolationRange = "range"
+	//ViolationAscending - the value must be greater than the previous one in the list (e.g. the thresholds of the pricing tiers)
+	ViolationAscending = "ascending"
+	//ViolationNonZero - the number must not be zero (e.g. a divisor)
+	ViolationNonZero = "nonZero"
+)
+
+//Violation describes why the value of a field is invalid
+type Violation struct {
+	//Field is the path of the field in the JSON representation (e.g. unitPrice.currency). Empty when the violation is not caused by a field
+	Field string `json:"field,omitempty"`
+
+	Code string `json:"code"`
+
+	Message string `json:"message"`
+
+	//Args fill the message of the Code in the catalogs (e.g. the field name)
+	Args []interface{} `json:"-"`
+}
+
+//NewViolation builds a Violation of the field with the English message of the code, filled with the args
+func NewViolation(field string, code string, args ...interface{}) Violation {
+	return Violation{Field: field, Code: code, Message: English.Message(violationMessageKey(code), args...), Args: args}
+}
+
+//Localize translates the message of the Violation
+func (violation Violation) Localize(locale *Locale) Violation {
+	if violation.Args != nil {
+		violation.Message = locale.Message(violationMessageKey(violation.Code), violation.Args...)
+	}
+	return violation
+}
+
+func violationMessageKey(code string) string {
+	return "violation." + code
+}
+
+//Violations collects every Violation found by a validation
+type Violations []Violation
+
+//Add a Violation of the field
+func (violations *Violations) Add(field string, code string, args ...interface{}) {
+	*violations = append(*violations, NewViolation(field, code, args...))
+}
+
+//Err returns a ConstraintViolationError listing the violations, described by the message of the key, or nil if there is none
+func (violations Violations) Err(messageKey string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return ConstraintViolations(messageKey, violations...)
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"container/list"
+	"time"
+
+	"github.com/danilovalente/project-api/domain"
+)
+
+//entry is a Project kept by the lru until it expires
+type entry struct {
+	id        string
+	project   domain.Project
+	expiresAt time.Time
+}
+
+//lru keeps up to size Projects by ID for the ttl, evicting the least recently used. It is not safe for concurrent use
+type lru struct {
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func newLRU(size int, ttl time.Duration) *lru {
+	return &lru{size: size, ttl: ttl, now: time.Now, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+//get the Project by ID, removing it if it is expired
+func (cache *lru) get(id string) (domain.Project, bool) {
+	element, ok := cache.entries[id]
+	if !ok {
+		return domain.Project{}, false
+	}
+	entry := element.Value.(*entry)
+	if !cache.now().Before(entry.expiresAt) {
+		cache.remove(id)
+		return domain.Project{}, false
+	}
+	cache.order.MoveToFront(element)
+	return entry.project, true
+}
+
+//put the Project, evicting the least recently used one when the size is exceeded
+func (cache *lru) put(id string, project domain.Project) {
+	expiresAt := cache.now().Add(cache.ttl)
+	if element, ok := cache.entries[id]; ok {
+		element.Value = &entry{id: id, project: project, expiresAt: expiresAt}
+		cache.order.MoveToFront(element)
+		return
+	}
+	cache.entries[id] = cache.order.PushFront(&entry{id: id, project: project, expiresAt: expiresAt})
+	for cache.order.Len() > cache.size {
+		cache.remove(cache.order.Back().Value.(*entry).id)
+	}
+}
+
+//remove the Project by ID
+func (cache *lru) remove(id string) {
+	if element, ok := cache.entries[id]; ok {
+		cache.order.Remove(element)
+		delete(cache.entries, id)
+	}
+}
+
+//len returns the number of Projects kept, including the expired ones not removed yet
+func (cache *lru) len() int {
+	return cache.order.Len()
+}
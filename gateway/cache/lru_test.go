@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newLRU(2, time.Minute)
+	cache.put("1", domain.Project{Name: "One"})
+	cache.put("2", domain.Project{Name: "Two"})
+	_, _ = cache.get("1")
+	cache.put("3", domain.Project{Name: "Three"})
+
+	_, ok := cache.get("2")
+	assert.False(t, ok, "The least recently used Project should be evicted")
+	project, ok := cache.get("1")
+	assert.True(t, ok)
+	assert.Equal(t, "One", project.Name)
+	assert.Equal(t, 2, cache.len())
+}
+
+func TestLRUExpires(t *testing.T) {
+	now := time.Now()
+	cache := newLRU(2, time.Minute)
+	cache.now = func() time.Time { return now }
+	cache.put("1", domain.Project{Name: "One"})
+
+	now = now.Add(59 * time.Second)
+	_, ok := cache.get("1")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok = cache.get("1")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.len())
+}
+
+func TestLRUReplaces(t *testing.T) {
+	cache := newLRU(2, time.Minute)
+	cache.put("1", domain.Project{Name: "One"})
+	cache.put("1", domain.Project{Name: "Uno"})
+
+	project, _ := cache.get("1")
+	assert.Equal(t, "Uno", project.Name)
+	assert.Equal(t, 1, cache.len())
+}
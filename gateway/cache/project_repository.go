@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	//The metrics decorate the ProjectRepository before the cache, so they observe only the reads which miss it
+	_ "github.com/danilovalente/project-api/gateway/metrics"
+)
+
+//cacheMetricsName labels the hit and miss metrics of the ProjectRepository cache
+const cacheMetricsName = "ProjectRepository"
+
+//CachingProjectRepository decorates a ProjectRepository, reading through an in-process cache of the Projects by ID.
+//Update and Delete invalidate the cached Project, as do the ProjectEvent published by the other instances
+type CachingProjectRepository struct {
+	repository domain.ProjectRepository
+	cache      *lru
+	//generation is incremented by each invalidation, so a Project read before it is not cached afterwards
+	generation uint64
+	mutex      sync.Mutex
+}
+
+//NewCachingProjectRepository wraps the repository with a cache of up to size Projects, each one kept for the ttl
+func NewCachingProjectRepository(repository domain.ProjectRepository, size int, ttl time.Duration) *CachingProjectRepository {
+	return &CachingProjectRepository{repository: repository, cache: newLRU(size, ttl)}
+}
+
+//Get a Project by ID, from the cache when available
+func (repo *CachingProjectRepository) Get(ctx context.Context, id string) (*domain.Project, error) {
+	repo.mutex.Lock()
+	project, hit := repo.cache.get(id)
+	generation := repo.generation
+	repo.mutex.Unlock()
+	config.GetMetrics().ObserveCache(cacheMetricsName, hit)
+	if hit {
+		return copyProject(project), nil
+	}
+
+	loaded, err := repo.repository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+	if generation == repo.generation {
+		repo.cache.put(id, *copyProject(*loaded))
+	}
+	return loaded, nil
+}
+
+//copyProject copies the Project with its PricingTiers, so neither the callers nor the decorated repository share them with the cache
+func copyProject(project domain.Project) *domain.Project {
+	project.PricingTiers = append([]domain.PricingTier(nil), project.PricingTiers...)
+	return &project
+}
+
+//Save a new project in the collection
+func (repo *CachingProjectRepository) Save(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	return repo.repository.Save(ctx, project)
+}
+
+//Update a project in the collection, invalidating the cached one
+func (repo *CachingProjectRepository) Update(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	defer repo.Invalidate(project.ID.Hex())
+	return repo.repository.Update(ctx, project)
+}
+
+//GetAll Project, always from the decorated repository
+func (repo *CachingProjectRepository) GetAll(ctx context.Context, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	return repo.repository.GetAll(ctx, lastProjectID, pageSize)
+}
+
+//Delete moves a Project to the trash, invalidating the cached one
+func (repo *CachingProjectRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	defer repo.Invalidate(id)
+	return repo.repository.Delete(ctx, id, deletedBy)
+}
+
+//GetTrash lists the deleted Projects, always from the decorated repository
+func (repo *CachingProjectRepository) GetTrash(ctx context.Context, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	return repo.repository.GetTrash(ctx, lastProjectID, pageSize)
+}
+
+//Restore takes a Project back from the trash. The deleted Projects are not cached, so there is nothing to invalidate
+func (repo *CachingProjectRepository) Restore(ctx context.Context, id string) (*domain.Project, error) {
+	return repo.repository.Restore(ctx, id)
+}
+
+//Purge permanently removes the Projects deleted before the date, which were invalidated when they were deleted
+func (repo *CachingProjectRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return repo.repository.Purge(ctx, deletedBefore)
+}
+
+//Invalidate removes the Project from the cache
+func (repo *CachingProjectRepository) Invalidate(id string) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	repo.generation++
+	repo.cache.remove(id)
+}
+
+//handleEvent invalidates the Projects changed by any instance
+func (repo *CachingProjectRepository) handleEvent(event domain.ProjectEvent) {
+	if event.Type == domain.ProjectUpdatedEvent || event.Type == domain.ProjectDeletedEvent {
+		repo.Invalidate(event.ProjectID)
+	}
+}
+
+func decorateProjectRepository(component appcontext.Component) (appcontext.Component, error) {
+	repo := NewCachingProjectRepository(component.(domain.ProjectRepository), config.Values.CacheSize, config.Values.CacheTTL)
+	domain.GetEventBus().Subscribe(repo.handleEvent)
+	return repo, nil
+}
+
+func init() {
+	if !config.Values.CacheEnabled {
+		return
+	}
+	if config.Values.EventBus == config.EventBusNone {
+		appcontext.Current.Decorate(appcontext.ProjectRepository, decorateProjectRepository)
+	} else {
+		appcontext.Current.Decorate(appcontext.ProjectRepository, decorateProjectRepository, appcontext.EventBus)
+	}
+}
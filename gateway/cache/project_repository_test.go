@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/domain"
+	"github.com/danilovalente/project-api/gateway/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+//countingProjectRepository counts the Get calls reaching the decorated repository
+type countingProjectRepository struct {
+	*memory.ProjectRepository
+	gets  int
+	onGet func()
+}
+
+func (repo *countingProjectRepository) Get(ctx context.Context, id string) (*domain.Project, error) {
+	repo.gets++
+	project, err := repo.ProjectRepository.Get(ctx, id)
+	if repo.onGet != nil {
+		repo.onGet()
+	}
+	return project, err
+}
+
+func newCachingRepository(t *testing.T, size int, ttl time.Duration) (*CachingProjectRepository, *countingProjectRepository) {
+	backend := &countingProjectRepository{ProjectRepository: memory.NewProjectRepository()}
+	return NewCachingProjectRepository(backend, size, ttl), backend
+}
+
+func saveProject(t *testing.T, repo domain.ProjectRepository, name string) *domain.Project {
+	project, err := repo.Save(context.Background(), &domain.Project{
+		Name:      name,
+		UnitPrice: domain.Money{Amount: 10000, Currency: "EUR"},
+		TimeUnit:  "Hour",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
+
+func TestGetReadsThrough(t *testing.T) {
+	repo, backend := newCachingRepository(t, 10, time.Minute)
+	ctx := context.Background()
+	project := saveProject(t, repo, "Project API")
+
+	for i := 0; i < 3; i++ {
+		cached, err := repo.Get(ctx, project.ID.Hex())
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Project API", cached.Name)
+		}
+	}
+	assert.Equal(t, 1, backend.gets)
+
+	//The cached Project is not changed through the returned copies
+	cached, _ := repo.Get(ctx, project.ID.Hex())
+	cached.Name = "Changed"
+	cached, _ = repo.Get(ctx, project.ID.Hex())
+	assert.Equal(t, "Project API", cached.Name)
+}
+
+func TestGetDoesNotShareThePricingTiers(t *testing.T) {
+	repo, _ := newCachingRepository(t, 10, time.Minute)
+	ctx := context.Background()
+	project := saveProject(t, repo, "Project API")
+	project.PricingTiers = []domain.PricingTier{{From: "10", UnitPrice: domain.Money{Amount: 9000, Currency: "EUR"}}}
+	_, err := repo.Update(ctx, project)
+	assert.NoError(t, err)
+
+	//Changing the tiers of the loaded Project does not change the cached one
+	loaded, _ := repo.Get(ctx, project.ID.Hex())
+	loaded.PricingTiers[0].From = "20"
+	cached, _ := repo.Get(ctx, project.ID.Hex())
+	assert.Equal(t, "10", cached.PricingTiers[0].From)
+
+	//Nor changing the tiers of a cached one
+	cached.PricingTiers[0].UnitPrice.Amount = 1
+	cached.PricingTiers = append(cached.PricingTiers, domain.PricingTier{From: "100"})
+	cached, _ = repo.Get(ctx, project.ID.Hex())
+	assert.Equal(t, []domain.PricingTier{{From: "10", UnitPrice: domain.Money{Amount: 9000, Currency: "EUR"}}}, cached.PricingTiers)
+}
+
+func TestGetDoesNotCacheErrors(t *testing.T) {
+	repo, backend := newCachingRepository(t, 10, time.Minute)
+	ctx := context.Background()
+
+	_, err := repo.Get(ctx, "invalid")
+	assert.Error(t, err)
+	_, err = repo.Get(ctx, "invalid")
+	assert.Error(t, err)
+	assert.Equal(t, 2, backend.gets)
+}
+
+func TestUpdateInvalidates(t *testing.T) {
+	repo, _ := newCachingRepository(t, 10, time.Minute)
+	ctx := context.Background()
+	project := saveProject(t, repo, "Project API")
+	_, _ = repo.Get(ctx, project.ID.Hex())
+
+	project.Name = "Renamed"
+	_, err := repo.Update(ctx, project)
+	if assert.NoError(t, err) {
+		updated, _ := repo.Get(ctx, project.ID.Hex())
+		assert.Equal(t, "Renamed", updated.Name)
+	}
+}
+
+func TestDeleteInvalidates(t *testing.T) {
+	repo, _ := newCachingRepository(t, 10, time.Minute)
+	ctx := context.Background()
+	project := saveProject(t, repo, "Project API")
+	_, _ = repo.Get(ctx, project.ID.Hex())
+
+	assert.NoError(t, repo.Delete(ctx, project.ID.Hex(), "alice"))
+	_, err := repo.Get(ctx, project.ID.Hex())
+	assert.Error(t, err)
+}
+
+func TestEventsInvalidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		eventType   string
+		invalidated bool
+	}{
+		{name: "Updated", eventType: domain.ProjectUpdatedEvent, invalidated: true},
+		{name: "Deleted", eventType: domain.ProjectDeletedEvent, invalidated: true},
+		{name: "Created", eventType: domain.ProjectCreatedEvent, invalidated: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, backend := newCachingRepository(t, 10, time.Minute)
+			ctx := context.Background()
+			project := saveProject(t, repo, "Project API")
+			_, _ = repo.Get(ctx, project.ID.Hex())
+
+			repo.handleEvent(domain.NewProjectEvent(ctx, tt.eventType, project.ID.Hex()))
+			_, _ = repo.Get(ctx, project.ID.Hex())
+			if tt.invalidated {
+				assert.Equal(t, 2, backend.gets)
+			} else {
+				assert.Equal(t, 1, backend.gets)
+			}
+		})
+	}
+}
+
+func TestInvalidationDuringGetIsNotOverwritten(t *testing.T) {
+	repo, backend := newCachingRepository(t, 10, time.Minute)
+	ctx := context.Background()
+	project := saveProject(t, repo, "Project API")
+
+	//The Project is invalidated while the Get reads it from the decorated repository, so the read one may be stale
+	backend.onGet = func() { repo.Invalidate(project.ID.Hex()) }
+	_, err := repo.Get(ctx, project.ID.Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, repo.cache.len())
+}
@@ -1,6 +1,8 @@
 package customlog
 
 import (
+	"context"
+
 	"github.com/danilovalente/project-api/appcontext"
 	"github.com/danilovalente/project-api/config"
 	"go.uber.org/zap"
@@ -87,11 +89,28 @@ func (logger Logger) Fatalf(template string, args ...interface{}) {
 	logger.Sugar.Fatalf(template, args...)
 }
 
+//With adds structured context (key-value pairs) to the returned Logger
+func (logger Logger) With(args ...interface{}) config.Logger {
+	sugar := logger.Sugar.With(args...)
+	return Logger{Logger: sugar.Desugar(), Sugar: sugar}
+}
+
 //Sync flushes the log if needed
 func (logger Logger) Sync() {
 	_ = logger.Sugar.Sync()
 }
 
+//Start is a no-op, as the Logger is ready once it is built
+func (logger Logger) Start(ctx context.Context) error {
+	return nil
+}
+
+//Stop flushes the buffered log entries
+func (logger Logger) Stop(ctx context.Context) error {
+	logger.Sync()
+	return nil
+}
+
 func discoverLogLevel() zapcore.Level {
 	switch config.Values.LogLevel {
 	case "DEBUG":
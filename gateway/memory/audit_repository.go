@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//AuditRepository keeps the AuditEntry in memory, recorded by the ProjectRepository while it holds its own lock
+type AuditRepository struct {
+	entries []domain.AuditEntry
+	mutex   sync.RWMutex
+}
+
+//NewAuditRepository creates an empty AuditRepository
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{entries: make([]domain.AuditEntry, 0)}
+}
+
+//record appends the entry. The IDs are generated in ascending order, so the entries are kept ordered by ID
+func (repo *AuditRepository) record(entry domain.AuditEntry) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+	repo.entries = append(repo.entries, entry)
+}
+
+//Find the entries matching the query ordered by ID, starting after the LastEntryID
+func (repo *AuditRepository) Find(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	var lastEntry primitive.ObjectID
+	if strings.TrimSpace(query.LastEntryID) != "" {
+		var err error
+		lastEntry, err = primitive.ObjectIDFromHex(query.LastEntryID)
+		if err != nil {
+			return nil, domain.FieldViolation("lastEntryId", domain.ViolationFormat, "lastEntryId", query.LastEntryID)
+		}
+	}
+	//As in MongoDB, a limit of zero means no limit and a negative limit is the same as its absolute value
+	pageSize := query.PageSize
+	if pageSize < 0 {
+		pageSize = -pageSize
+	}
+
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+	entries := make([]*domain.AuditEntry, 0)
+	for index := range repo.entries {
+		entry := repo.entries[index]
+		if (lastEntry != primitive.NilObjectID && entry.ID.Hex() <= lastEntry.Hex()) || !query.Matches(&entry) {
+			continue
+		}
+		entry.Changes = append([]domain.FieldChange(nil), entry.Changes...)
+		entries = append(entries, &entry)
+		if pageSize > 0 && int64(len(entries)) == pageSize {
+			break
+		}
+	}
+	return entries, nil
+}
+
+func buildAuditRepository() appcontext.Component {
+	return NewAuditRepository()
+}
+
+func init() {
+	if config.Values.Repository != config.RepositoryMemory {
+		return
+	}
+	appcontext.Current.Add(appcontext.AuditRepository, buildAuditRepository)
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudit(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := appcontext.WithActor(context.Background(), "alice")
+	start := now()
+	saved, _ := repo.Save(ctx, newProject("Project API"))
+	other, _ := repo.Save(appcontext.WithActor(context.Background(), "bob"), newProject("Other"))
+	saved.Name = "Project API v2"
+	_, err := repo.Update(ctx, saved)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Delete(ctx, saved.ID.Hex(), "alice"))
+	_, err = repo.Restore(ctx, saved.ID.Hex())
+	assert.NoError(t, err)
+	//The failed changes are not recorded
+	_, err = repo.Save(ctx, newProject("Other"))
+	assert.Error(t, err)
+
+	history, err := repo.Audit().Find(ctx, domain.AuditQuery{Entity: domain.AuditEntityProject, EntityID: saved.ID.Hex()})
+	if assert.NoError(t, err) && assert.Len(t, history, 4) {
+		for index, action := range []string{domain.AuditCreated, domain.AuditUpdated, domain.AuditDeleted, domain.AuditRestored} {
+			assert.Equal(t, action, history[index].Action)
+			assert.Equal(t, "alice", history[index].Actor)
+		}
+		assert.Equal(t, []domain.FieldChange{{Field: "name", Before: "Project API", After: "Project API v2"}}, history[1].Changes)
+		assert.Contains(t, history[2].Changes, domain.FieldChange{Field: "deletedBy", After: "alice"})
+		assert.Contains(t, history[3].Changes, domain.FieldChange{Field: "deletedBy", Before: "alice"})
+	}
+
+	//Paging
+	page, err := repo.Audit().Find(ctx, domain.AuditQuery{EntityID: saved.ID.Hex(), LastEntryID: history[0].ID.Hex(), PageSize: 2})
+	if assert.NoError(t, err) && assert.Len(t, page, 2) {
+		assert.Equal(t, history[1].ID, page[0].ID)
+	}
+	_, err = repo.Audit().Find(ctx, domain.AuditQuery{LastEntryID: "invalid"})
+	assert.Equal(t, 400, errorCode(err))
+
+	//Filters
+	byActor, _ := repo.Audit().Find(ctx, domain.AuditQuery{Actor: "bob"})
+	if assert.Len(t, byActor, 1) {
+		assert.Equal(t, other.ID.Hex(), byActor[0].EntityID)
+	}
+	all, _ := repo.Audit().Find(ctx, domain.AuditQuery{From: start, Until: now().Add(time.Second)})
+	assert.Len(t, all, 5)
+	none, _ := repo.Audit().Find(ctx, domain.AuditQuery{Until: start})
+	assert.Empty(t, none)
+
+	//The history is kept after the Project is purged, which is also recorded
+	assert.NoError(t, repo.Delete(ctx, saved.ID.Hex(), "alice"))
+	_, err = repo.Purge(appcontext.WithVerifiedActor(context.Background(), domain.SystemActor), now().Add(time.Second))
+	assert.NoError(t, err)
+	history, _ = repo.Audit().Find(ctx, domain.AuditQuery{EntityID: saved.ID.Hex()})
+	if assert.Len(t, history, 6) {
+		purged := history[5]
+		assert.Equal(t, domain.AuditPurged, purged.Action)
+		assert.Equal(t, domain.SystemActor, purged.Actor)
+		assert.Contains(t, purged.Changes, domain.FieldChange{Field: "name", Before: "Project API v2"})
+	}
+}
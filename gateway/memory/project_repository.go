@@ -0,0 +1,295 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//ProjectRepository keeps the Projects in memory, following the same semantics of the MongoDB gateway.
+//Each change is recorded in the AuditRepository and the ProjectVersionRepository while the lock is held
+type ProjectRepository struct {
+	projects map[primitive.ObjectID]domain.Project
+	audit    *AuditRepository
+	versions *ProjectVersionRepository
+	mutex    sync.RWMutex
+}
+
+//NewProjectRepository creates an empty ProjectRepository, recording the changes in its own AuditRepository and ProjectVersionRepository
+func NewProjectRepository() *ProjectRepository {
+	return NewAuditedProjectRepository(NewAuditRepository(), NewProjectVersionRepository())
+}
+
+//NewAuditedProjectRepository creates an empty ProjectRepository, recording the changes in the audit and the versions
+func NewAuditedProjectRepository(audit *AuditRepository, versions *ProjectVersionRepository) *ProjectRepository {
+	return &ProjectRepository{projects: make(map[primitive.ObjectID]domain.Project), audit: audit, versions: versions}
+}
+
+//Audit returns the AuditRepository in which the changes are recorded
+func (repo *ProjectRepository) Audit() *AuditRepository {
+	return repo.audit
+}
+
+//Versions returns the ProjectVersionRepository in which the changes are recorded
+func (repo *ProjectRepository) Versions() *ProjectVersionRepository {
+	return repo.versions
+}
+
+//track records the change of the Project, made at the date, in the audit and the versions. It must be called holding the mutex
+func (repo *ProjectRepository) track(ctx context.Context, action string, before *domain.Project, after *domain.Project, date time.Time) {
+	repo.audit.record(domain.NewProjectAuditEntry(ctx, action, before, after))
+	repo.versions.record(ctx, after, date)
+}
+
+//now returns the current time with the millisecond precision of the dates stored in MongoDB
+func now() time.Time {
+	return time.Now().Truncate(time.Millisecond)
+}
+
+//Get a Project by ID
+func (repo *ProjectRepository) Get(ctx context.Context, id string) (*domain.Project, error) {
+	projectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", id)
+	}
+
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+	project, ok := repo.projects[projectID]
+	if !ok || project.Deleted() {
+		return nil, domain.NotFound(fmt.Sprintf("Could not find Project with the ID: %s", id))
+	}
+	return copyProject(project), nil
+}
+
+//Save a new project in the collection
+func (repo *ProjectRepository) Save(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	if primitive.NilObjectID != project.ID {
+		return nil, domain.InternalError("The Save method should not be used for updating. Please use Update instead")
+	}
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+	if repo.nameTaken(project.Name, primitive.NilObjectID) {
+		return nil, projectNameAlreadyExists(project)
+	}
+	project.ID = primitive.NewObjectID()
+	project.DateCreated = now()
+	project.Undelete()
+	repo.projects[project.ID] = *copyProject(*project)
+	repo.track(ctx, domain.AuditCreated, nil, project, project.DateCreated)
+	return project, nil
+}
+
+//Update a project in the collection
+func (repo *ProjectRepository) Update(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	existentProject, ok := repo.projects[project.ID]
+	if !ok || existentProject.Deleted() {
+		return nil, domain.NotFound(fmt.Sprintf("Could not find Project with the ID: %s", project.ID.Hex()))
+	}
+	if repo.nameTaken(project.Name, project.ID) {
+		return nil, projectNameAlreadyExists(project)
+	}
+	project.DateCreated = existentProject.DateCreated
+	project.DateUpdated = now()
+	project.Undelete()
+	repo.projects[project.ID] = *copyProject(*project)
+	repo.track(ctx, domain.AuditUpdated, &existentProject, project, project.DateUpdated)
+	return project, nil
+}
+
+//GetAll Project ordered by ID, starting after the lastProjectID
+func (repo *ProjectRepository) GetAll(ctx context.Context, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	return repo.list(lastProjectID, pageSize, false)
+}
+
+//GetTrash lists the deleted Projects ordered by ID, starting after the lastProjectID
+func (repo *ProjectRepository) GetTrash(ctx context.Context, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	return repo.list(lastProjectID, pageSize, true)
+}
+
+//list the Projects in the trash, or out of it, ordered by ID, starting after the lastProjectID
+func (repo *ProjectRepository) list(lastProjectID string, pageSize int64, deleted bool) ([]*domain.Project, error) {
+	var lastProject primitive.ObjectID
+	if strings.TrimSpace(lastProjectID) != "" {
+		var err error
+		lastProject, err = primitive.ObjectIDFromHex(lastProjectID)
+		if err != nil {
+			return nil, domain.FieldViolation("lastProjectId", domain.ViolationFormat, "lastProjectId", lastProjectID)
+		}
+	}
+
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+	projectList := make([]*domain.Project, 0)
+	for projectID, project := range repo.projects {
+		if project.Deleted() != deleted || (lastProject != primitive.NilObjectID && projectID.Hex() <= lastProject.Hex()) {
+			continue
+		}
+		projectList = append(projectList, copyProject(project))
+	}
+	sort.Slice(projectList, func(i, j int) bool {
+		return projectList[i].ID.Hex() < projectList[j].ID.Hex()
+	})
+	//As in MongoDB, a limit of zero means no limit and a negative limit is the same as its absolute value
+	if pageSize < 0 {
+		pageSize = -pageSize
+	}
+	if pageSize > 0 && int64(len(projectList)) > pageSize {
+		projectList = projectList[:pageSize]
+	}
+	return projectList, nil
+}
+
+//Delete moves the Project with the ID to the trash
+func (repo *ProjectRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	projectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", id)
+	}
+
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+	project, ok := repo.projects[projectID]
+	if !ok || project.Deleted() {
+		return domain.NotFound(fmt.Sprintf("Could not find Project with the ID: %s", id))
+	}
+	before := project
+	project.DeletedAt = now()
+	project.DeletedBy = deletedBy
+	repo.projects[projectID] = project
+	repo.track(ctx, domain.AuditDeleted, &before, &project, project.DeletedAt)
+	return nil
+}
+
+//Restore takes the deleted Project with the ID back from the trash
+func (repo *ProjectRepository) Restore(ctx context.Context, id string) (*domain.Project, error) {
+	projectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", id)
+	}
+
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+	project, ok := repo.projects[projectID]
+	if !ok || !project.Deleted() {
+		return nil, domain.NotFound(fmt.Sprintf("Could not find the deleted Project with the ID: %s", id))
+	}
+	if repo.nameTaken(project.Name, projectID) {
+		return nil, projectNameAlreadyExists(&project)
+	}
+	before := project
+	project.Undelete()
+	repo.projects[projectID] = project
+	repo.track(ctx, domain.AuditRestored, &before, &project, now())
+	return copyProject(project), nil
+}
+
+//Purge permanently removes the Projects deleted before the date, recording an AuditEntry for each one. Their ProjectVersion are kept
+func (repo *ProjectRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+	purged := int64(0)
+	for projectID, project := range repo.projects {
+		if project.Deleted() && project.DeletedAt.Before(deletedBefore) {
+			delete(repo.projects, projectID)
+			purgedProject := project
+			repo.audit.record(domain.NewProjectAuditEntry(ctx, domain.AuditPurged, &purgedProject, nil))
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+//nameTaken checks, ignoring the case as the MongoDB unique index, if another Project not deleted has the name.
+//It must be called holding the mutex
+func (repo *ProjectRepository) nameTaken(name string, projectID primitive.ObjectID) bool {
+	key := domain.FoldName(name)
+	for existentID, existentProject := range repo.projects {
+		if existentID != projectID && !existentProject.Deleted() && domain.FoldName(existentProject.Name) == key {
+			return true
+		}
+	}
+	return false
+}
+
+//copyProject copies the Project with its own PricingTiers, so the stored Projects share no backing array with the callers
+func copyProject(project domain.Project) *domain.Project {
+	project.PricingTiers = append([]domain.PricingTier(nil), project.PricingTiers...)
+	return &project
+}
+
+func projectNameAlreadyExists(project *domain.Project) domain.AlreadyExistsError {
+	return domain.AlreadyExistsForField("name", fmt.Sprintf("A Project named %s already exists", project.Name))
+}
+
+//Load stores the Projects as they are, generating the id and the creation date of the Projects without them.
+//Each Project gets its first version, dated by its last change, but no audit entry
+func (repo *ProjectRepository) Load(projects ...domain.Project) error {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+	for _, project := range projects {
+		if valid, err := project.Valid(); !valid {
+			return fmt.Errorf("Invalid Project %s: %w", project.Name, err)
+		}
+		if !project.Deleted() && repo.nameTaken(project.Name, project.ID) {
+			return projectNameAlreadyExists(&project)
+		}
+		if project.ID == primitive.NilObjectID {
+			project.ID = primitive.NewObjectID()
+		}
+		if project.DateCreated.IsZero() {
+			project.DateCreated = now()
+		}
+		repo.projects[project.ID] = *copyProject(project)
+		repo.versions.record(context.Background(), &project, project.LastChanged())
+	}
+	return nil
+}
+
+//Seed loads the Projects from a JSON fixture file containing an array of Projects
+func (repo *ProjectRepository) Seed(fileName string) error {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return fmt.Errorf("Could not read the Project fixture file %s: %w", fileName, err)
+	}
+	projects := make([]domain.Project, 0)
+	if err = json.Unmarshal(content, &projects); err != nil {
+		return fmt.Errorf("Could not parse the Project fixture file %s: %w", fileName, err)
+	}
+	if err = repo.Load(projects...); err != nil {
+		return fmt.Errorf("Could not load the Project fixture file %s: %w", fileName, err)
+	}
+	return nil
+}
+
+func buildProjectRepository() (appcontext.Component, error) {
+	audit := appcontext.Current.Get(appcontext.AuditRepository).(*AuditRepository)
+	versions := appcontext.Current.Get(appcontext.ProjectVersionRepository).(*ProjectVersionRepository)
+	repo := NewAuditedProjectRepository(audit, versions)
+	if config.Values.RepositorySeedFile != "" {
+		if err := repo.Seed(config.Values.RepositorySeedFile); err != nil {
+			return nil, err
+		}
+	}
+	return repo, nil
+}
+
+func init() {
+	if config.Values.Repository != config.RepositoryMemory {
+		return
+	}
+	appcontext.Current.AddFactory(appcontext.ProjectRepository, buildProjectRepository, appcontext.AuditRepository, appcontext.ProjectVersionRepository)
+}
@@ -0,0 +1,332 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/domain"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newProject(name string) *domain.Project {
+	return &domain.Project{
+		Name:      name,
+		UnitPrice: domain.Money{Amount: 10000, Currency: "EUR"},
+		TimeUnit:  "Hour",
+	}
+}
+
+func errorCode(err error) int {
+	if identifiableError, ok := err.(domain.IdentifiableError); ok {
+		return identifiableError.GetCode()
+	}
+	return 0
+}
+
+func TestSave(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := context.Background()
+
+	project, err := repo.Save(ctx, newProject("Project API"))
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, primitive.NilObjectID, project.ID)
+		assert.False(t, project.DateCreated.IsZero())
+		assert.True(t, project.DateUpdated.IsZero())
+	}
+
+	_, err = repo.Save(ctx, project)
+	assert.Equal(t, 500, errorCode(err))
+}
+
+func TestGet(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := context.Background()
+	saved, _ := repo.Save(ctx, newProject("Project API"))
+
+	tests := []struct {
+		name         string
+		projectID    string
+		expectedCode int
+	}{
+		{name: "Found", projectID: saved.ID.Hex()},
+		{name: "Not Found", projectID: primitive.NewObjectID().Hex(), expectedCode: 404},
+		{name: "Invalid ID", projectID: "invalid", expectedCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project, err := repo.Get(ctx, tt.projectID)
+			assert.Equal(t, tt.expectedCode, errorCode(err))
+			if tt.expectedCode == 0 {
+				assert.Equal(t, *saved, *project)
+			}
+		})
+	}
+}
+
+func TestGetReturnsACopy(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := context.Background()
+	saved, _ := repo.Save(ctx, newProject("Project API"))
+
+	project, _ := repo.Get(ctx, saved.ID.Hex())
+	project.Name = "Changed"
+
+	stored, _ := repo.Get(ctx, saved.ID.Hex())
+	assert.Equal(t, "Project API", stored.Name)
+}
+
+func TestStoredProjectsDoNotShareThePricingTiers(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := context.Background()
+	tiers := func() []domain.PricingTier {
+		return []domain.PricingTier{{From: "10", UnitPrice: domain.Money{Amount: 9000, Currency: "EUR"}}}
+	}
+	project := newProject("Project API")
+	project.PricingTiers = tiers()
+	saved, _ := repo.Save(ctx, project)
+	loaded := newProject("Loaded")
+	loaded.ID = primitive.NewObjectID()
+	loaded.PricingTiers = tiers()
+	assert.NoError(t, repo.Load(*loaded))
+
+	//Changing the tiers of the saved and loaded Projects does not change the stored ones
+	saved.PricingTiers[0].From = "20"
+	loaded.PricingTiers[0].From = "20"
+	//Nor changing the tiers of the returned ones
+	got, _ := repo.Get(ctx, saved.ID.Hex())
+	got.PricingTiers[0].UnitPrice.Amount = 1
+	all, _ := repo.GetAll(ctx, "", 0)
+	for _, listed := range all {
+		listed.PricingTiers[0].UnitPrice.Amount = 1
+	}
+
+	for _, id := range []primitive.ObjectID{saved.ID, loaded.ID} {
+		stored, _ := repo.Get(ctx, id.Hex())
+		assert.Equal(t, tiers(), stored.PricingTiers)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := context.Background()
+	saved, _ := repo.Save(ctx, newProject("Project API"))
+	dateCreated := saved.DateCreated
+
+	changed := newProject("Renamed")
+	changed.ID = saved.ID
+	project, err := repo.Update(ctx, changed)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Renamed", project.Name)
+		assert.Equal(t, dateCreated, project.DateCreated)
+		assert.False(t, project.DateUpdated.IsZero())
+	}
+
+	missing := newProject("Missing")
+	missing.ID = primitive.NewObjectID()
+	_, err = repo.Update(ctx, missing)
+	assert.Equal(t, 404, errorCode(err))
+}
+
+func TestGetAll(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := context.Background()
+	ids := make([]string, 0)
+	for i := 0; i < 5; i++ {
+		project, _ := repo.Save(ctx, newProject(fmt.Sprintf("Project %d", i)))
+		ids = append(ids, project.ID.Hex())
+	}
+
+	tests := []struct {
+		name          string
+		lastProjectID string
+		pageSize      int64
+		expectedIDs   []string
+		expectedCode  int
+	}{
+		{name: "First Page", pageSize: 2, expectedIDs: ids[:2]},
+		{name: "Next Page", lastProjectID: ids[1], pageSize: 2, expectedIDs: ids[2:4]},
+		{name: "Last Page", lastProjectID: ids[3], pageSize: 2, expectedIDs: ids[4:]},
+		{name: "No Limit", pageSize: 0, expectedIDs: ids},
+		{name: "Negative Limit", pageSize: -3, expectedIDs: ids[:3]},
+		{name: "Invalid ID", lastProjectID: "invalid", pageSize: 2, expectedCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projects, err := repo.GetAll(ctx, tt.lastProjectID, tt.pageSize)
+			assert.Equal(t, tt.expectedCode, errorCode(err))
+			if tt.expectedCode == 0 {
+				projectIDs := make([]string, 0)
+				for _, project := range projects {
+					projectIDs = append(projectIDs, project.ID.Hex())
+				}
+				assert.Equal(t, tt.expectedIDs, projectIDs)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := context.Background()
+	saved, _ := repo.Save(ctx, newProject("Project API"))
+
+	assert.NoError(t, repo.Delete(ctx, saved.ID.Hex(), "alice"))
+	assert.Equal(t, 404, errorCode(repo.Delete(ctx, saved.ID.Hex(), "alice")))
+	assert.Equal(t, 400, errorCode(repo.Delete(ctx, "invalid", "alice")))
+
+	//The deleted Project is kept in the trash, out of the other reads
+	_, err := repo.Get(ctx, saved.ID.Hex())
+	assert.Equal(t, 404, errorCode(err))
+	_, err = repo.Update(ctx, saved)
+	assert.Equal(t, 404, errorCode(err))
+	projects, _ := repo.GetAll(ctx, "", 0)
+	assert.Empty(t, projects)
+	trash, err := repo.GetTrash(ctx, "", 0)
+	if assert.NoError(t, err) && assert.Len(t, trash, 1) {
+		assert.Equal(t, saved.ID, trash[0].ID)
+		assert.Equal(t, "alice", trash[0].DeletedBy)
+		assert.True(t, trash[0].Deleted())
+	}
+	//The deleted Project releases its name
+	_, err = repo.Save(ctx, newProject("project api"))
+	assert.NoError(t, err)
+}
+
+func TestRestore(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := context.Background()
+	saved, _ := repo.Save(ctx, newProject("Project API"))
+
+	_, err := repo.Restore(ctx, saved.ID.Hex())
+	assert.Equal(t, 404, errorCode(err))
+	_, err = repo.Restore(ctx, "invalid")
+	assert.Equal(t, 400, errorCode(err))
+
+	assert.NoError(t, repo.Delete(ctx, saved.ID.Hex(), "alice"))
+	restored, err := repo.Restore(ctx, saved.ID.Hex())
+	if assert.NoError(t, err) {
+		assert.False(t, restored.Deleted())
+		assert.Empty(t, restored.DeletedBy)
+	}
+	project, err := repo.Get(ctx, saved.ID.Hex())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Project API", project.Name)
+	}
+	trash, _ := repo.GetTrash(ctx, "", 0)
+	assert.Empty(t, trash)
+
+	//The Project is not restored while another one has its name
+	assert.NoError(t, repo.Delete(ctx, saved.ID.Hex(), "alice"))
+	_, err = repo.Save(ctx, newProject("PROJECT API"))
+	assert.NoError(t, err)
+	_, err = repo.Restore(ctx, saved.ID.Hex())
+	if alreadyExists, ok := err.(domain.AlreadyExistsError); assert.True(t, ok) {
+		assert.Equal(t, "name", alreadyExists.Field)
+	}
+	trash, _ = repo.GetTrash(ctx, "", 0)
+	assert.Len(t, trash, 1)
+}
+
+func TestPurge(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := context.Background()
+	kept, _ := repo.Save(ctx, newProject("Kept"))
+	old, _ := repo.Save(ctx, newProject("Old"))
+	recent, _ := repo.Save(ctx, newProject("Recent"))
+	assert.NoError(t, repo.Delete(ctx, old.ID.Hex(), "alice"))
+	cutoff := now().Add(time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+	assert.NoError(t, repo.Delete(ctx, recent.ID.Hex(), "alice"))
+
+	purged, err := repo.Purge(ctx, cutoff)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(1), purged)
+	}
+	_, err = repo.Get(ctx, kept.ID.Hex())
+	assert.NoError(t, err)
+	trash, _ := repo.GetTrash(ctx, "", 0)
+	if assert.Len(t, trash, 1) {
+		assert.Equal(t, recent.ID, trash[0].ID)
+	}
+	_, err = repo.Restore(ctx, old.ID.Hex())
+	assert.Equal(t, 404, errorCode(err))
+	//The purged Project releases its name
+	_, err = repo.Save(ctx, newProject("Old"))
+	assert.NoError(t, err)
+}
+
+func TestSeed(t *testing.T) {
+	projectID := primitive.NewObjectID()
+	fixture := `[
+		{"id":"` + projectID.Hex() + `","name":"Project API","unitPrice":{"amount":10000,"currency":"EUR"},"timeUnit":"Hour"},
+		{"name":"Project UI","unitPrice":{"amount":8000,"currency":"USD"},"timeUnit":"Day"}
+	]`
+	fileName := filepath.Join(t.TempDir(), "projects.json")
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte(fixture), 0600))
+
+	repo := NewProjectRepository()
+	if assert.NoError(t, repo.Seed(fileName)) {
+		projects, _ := repo.GetAll(context.Background(), "", 0)
+		assert.Len(t, projects, 2)
+		project, err := repo.Get(context.Background(), projectID.Hex())
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Project API", project.Name)
+			assert.False(t, project.DateCreated.IsZero())
+		}
+	}
+
+	assert.Error(t, repo.Seed(filepath.Join(t.TempDir(), "missing.json")))
+	invalidFileName := filepath.Join(t.TempDir(), "invalid.json")
+	assert.NoError(t, ioutil.WriteFile(invalidFileName, []byte(`[{"name":""}]`), 0600))
+	assert.Error(t, repo.Seed(invalidFileName))
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			project, err := repo.Save(ctx, newProject(fmt.Sprintf("Project %d", i)))
+			if assert.NoError(t, err) {
+				_, _ = repo.GetAll(ctx, "", 10)
+				_, _ = repo.Update(ctx, project)
+				assert.NoError(t, repo.Delete(ctx, project.ID.Hex(), "alice"))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	projects, _ := repo.GetAll(ctx, "", 0)
+	assert.Empty(t, projects)
+}
+
+func TestUniqueName(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := context.Background()
+	saved, _ := repo.Save(ctx, newProject("Project API"))
+	other, _ := repo.Save(ctx, newProject("Project UI"))
+
+	_, err := repo.Save(ctx, newProject("project api"))
+	if alreadyExists, ok := err.(domain.AlreadyExistsError); assert.True(t, ok) {
+		assert.Equal(t, 409, alreadyExists.Code)
+		assert.Equal(t, "name", alreadyExists.Field)
+	}
+
+	other.Name = "PROJECT API"
+	_, err = repo.Update(ctx, other)
+	assert.Equal(t, 409, errorCode(err))
+
+	//A Project keeps its own name, even changing the case
+	saved.Name = "Project Api"
+	_, err = repo.Update(ctx, saved)
+	assert.NoError(t, err)
+}
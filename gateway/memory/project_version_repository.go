@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//ProjectVersionRepository keeps the ProjectVersion in memory, recorded by the ProjectRepository while it holds its own lock
+type ProjectVersionRepository struct {
+	versions map[primitive.ObjectID][]domain.ProjectVersion
+	mutex    sync.RWMutex
+}
+
+//NewProjectVersionRepository creates an empty ProjectVersionRepository
+func NewProjectVersionRepository() *ProjectVersionRepository {
+	return &ProjectVersionRepository{versions: make(map[primitive.ObjectID][]domain.ProjectVersion)}
+}
+
+//record appends the next version of the Project, changed at the date
+func (repo *ProjectVersionRepository) record(ctx context.Context, project *domain.Project, date time.Time) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+	versions := repo.versions[project.ID]
+	repo.versions[project.ID] = append(versions, domain.NewProjectVersion(ctx, int64(len(versions))+1, project, date))
+}
+
+//Get the version number of the Project
+func (repo *ProjectVersionRepository) Get(ctx context.Context, projectID string, number int64) (*domain.ProjectVersion, error) {
+	versions, err := repo.list(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if number < 1 || number > int64(len(versions)) {
+		return nil, domain.NotFound(fmt.Sprintf("Could not find the version %d of the Project with the ID: %s", number, projectID))
+	}
+	return copyVersion(versions[number-1]), nil
+}
+
+//GetAsOf gets the version of the Project current at the date, the last one created until then
+func (repo *ProjectVersionRepository) GetAsOf(ctx context.Context, projectID string, asOf time.Time) (*domain.ProjectVersion, error) {
+	versions, err := repo.list(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for index := len(versions) - 1; index >= 0; index-- {
+		if !versions[index].DateCreated.After(asOf) {
+			return copyVersion(versions[index]), nil
+		}
+	}
+	return nil, domain.NotFound(fmt.Sprintf("Could not find a version of the Project with the ID: %s as of %s", projectID, asOf.Format(time.RFC3339)))
+}
+
+//list the versions of the Project, ordered by number
+func (repo *ProjectVersionRepository) list(projectID string) ([]domain.ProjectVersion, error) {
+	id, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", projectID)
+	}
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+	return repo.versions[id], nil
+}
+
+//copyVersion returns a copy of the version which does not share its pricing tiers, keeping the stored version immutable
+func copyVersion(version domain.ProjectVersion) *domain.ProjectVersion {
+	version.Project.PricingTiers = append([]domain.PricingTier(nil), version.Project.PricingTiers...)
+	return &version
+}
+
+func buildProjectVersionRepository() appcontext.Component {
+	return NewProjectVersionRepository()
+}
+
+func init() {
+	if config.Values.Repository != config.RepositoryMemory {
+		return
+	}
+	appcontext.Current.Add(appcontext.ProjectVersionRepository, buildProjectVersionRepository)
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/domain"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestProjectVersions(t *testing.T) {
+	repo := NewProjectRepository()
+	ctx := appcontext.WithActor(context.Background(), "alice")
+	saved, _ := repo.Save(ctx, newProject("Project API"))
+	id := saved.ID.Hex()
+	time.Sleep(2 * time.Millisecond)
+	saved.UnitPrice = domain.Money{Amount: 12000, Currency: "EUR"}
+	_, err := repo.Update(ctx, saved)
+	assert.NoError(t, err)
+	time.Sleep(2 * time.Millisecond)
+	assert.NoError(t, repo.Delete(ctx, id, "alice"))
+	time.Sleep(2 * time.Millisecond)
+	_, err = repo.Restore(ctx, id)
+	assert.NoError(t, err)
+
+	versions := make([]*domain.ProjectVersion, 0)
+	for number := int64(1); number <= 4; number++ {
+		version, err := repo.Versions().Get(ctx, id, number)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, number, version.Number)
+		assert.Equal(t, "alice", version.CreatedBy)
+		assert.Equal(t, saved.ID, version.Project.ID)
+		versions = append(versions, version)
+	}
+	assert.Equal(t, int64(10000), versions[0].Project.UnitPrice.Amount)
+	assert.Equal(t, int64(12000), versions[1].Project.UnitPrice.Amount)
+	assert.True(t, versions[2].Project.Deleted())
+	assert.False(t, versions[3].Project.Deleted())
+	for _, number := range []int64{0, 5} {
+		_, err = repo.Versions().Get(ctx, id, number)
+		assert.Equal(t, 404, errorCode(err))
+	}
+	_, err = repo.Versions().Get(ctx, "invalid", 1)
+	assert.Equal(t, 400, errorCode(err))
+
+	tests := []struct {
+		name           string
+		asOf           time.Time
+		expectedNumber int64
+		expectedCode   int
+	}{
+		{name: "Before the creation", asOf: versions[0].DateCreated.Add(-time.Millisecond), expectedCode: 404},
+		{name: "At the creation", asOf: versions[0].DateCreated, expectedNumber: 1},
+		{name: "Between the changes", asOf: versions[2].DateCreated.Add(-time.Millisecond), expectedNumber: 2},
+		{name: "Now", asOf: time.Now(), expectedNumber: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := repo.Versions().GetAsOf(ctx, id, tt.asOf)
+			assert.Equal(t, tt.expectedCode, errorCode(err))
+			if tt.expectedCode == 0 {
+				assert.Equal(t, tt.expectedNumber, version.Number)
+			}
+		})
+	}
+
+	//The versions are immutable and kept after the Project is purged
+	versions[0].Project.Name = "Changed"
+	assert.NoError(t, repo.Delete(ctx, id, "alice"))
+	_, err = repo.Purge(ctx, now().Add(time.Second))
+	assert.NoError(t, err)
+	version, err := repo.Versions().Get(ctx, id, 1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Project API", version.Project.Name)
+	}
+}
+
+func TestLoadProjectVersion(t *testing.T) {
+	repo := NewProjectRepository()
+	project := *newProject("Project API")
+	project.ID = primitive.NewObjectID()
+	project.DateCreated = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	project.DateUpdated = time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, repo.Load(project))
+
+	version, err := repo.Versions().GetAsOf(context.Background(), project.ID.Hex(), project.DateUpdated)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(1), version.Number)
+		assert.Equal(t, domain.AnonymousActor, version.CreatedBy)
+	}
+	_, err = repo.Versions().GetAsOf(context.Background(), project.ID.Hex(), project.DateCreated)
+	assert.Equal(t, 404, errorCode(err))
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//TaxRateRepository keeps the TaxRates in memory, loaded from the configuration
+type TaxRateRepository struct {
+	rates []domain.TaxRate
+	mutex sync.RWMutex
+}
+
+//NewTaxRateRepository creates an empty TaxRateRepository
+func NewTaxRateRepository() *TaxRateRepository {
+	return &TaxRateRepository{rates: make([]domain.TaxRate, 0)}
+}
+
+//Find the rates charged in the jurisdiction (including the ones of its country) for the category at the date, ordered by jurisdiction and name
+func (repo *TaxRateRepository) Find(ctx context.Context, jurisdiction string, category string, date time.Time) ([]domain.TaxRate, error) {
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+	rates := make([]domain.TaxRate, 0)
+	for _, rate := range repo.rates {
+		if rate.AppliesTo(jurisdiction, category, date) {
+			rates = append(rates, rate)
+		}
+	}
+	sort.SliceStable(rates, func(i, j int) bool {
+		if rates[i].Jurisdiction != rates[j].Jurisdiction {
+			return rates[i].Jurisdiction < rates[j].Jurisdiction
+		}
+		return rates[i].Name < rates[j].Name
+	})
+	return rates, nil
+}
+
+//Load validates and stores the TaxRates, normalizing the jurisdictions to upper case
+func (repo *TaxRateRepository) Load(rates ...domain.TaxRate) error {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+	for _, rate := range rates {
+		if valid, err := rate.Valid(); !valid {
+			return fmt.Errorf("Invalid Tax Rate %s in %s: %w", rate.Name, rate.Jurisdiction, err)
+		}
+		rate.Jurisdiction = strings.ToUpper(strings.TrimSpace(rate.Jurisdiction))
+		repo.rates = append(repo.rates, rate)
+	}
+	return nil
+}
+
+//LoadFile loads the TaxRates from a JSON file containing an array of TaxRates
+func (repo *TaxRateRepository) LoadFile(fileName string) error {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return fmt.Errorf("Could not read the Tax Rates file %s: %w", fileName, err)
+	}
+	rates := make([]domain.TaxRate, 0)
+	if err = json.Unmarshal(content, &rates); err != nil {
+		return fmt.Errorf("Could not parse the Tax Rates file %s: %w", fileName, err)
+	}
+	if err = repo.Load(rates...); err != nil {
+		return fmt.Errorf("Could not load the Tax Rates file %s: %w", fileName, err)
+	}
+	return nil
+}
+
+func buildTaxRateRepository() (appcontext.Component, error) {
+	repo := NewTaxRateRepository()
+	if config.Values.TaxRatesFile != "" {
+		if err := repo.LoadFile(config.Values.TaxRatesFile); err != nil {
+			return nil, err
+		}
+	}
+	return repo, nil
+}
+
+func init() {
+	if config.Values.TaxRatesSource != config.TaxRatesConfig {
+		return
+	}
+	appcontext.Current.AddFactory(appcontext.TaxRateRepository, buildTaxRateRepository)
+}
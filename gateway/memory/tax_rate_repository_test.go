@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaxRateRepositoryFind(t *testing.T) {
+	changeDate := time.Date(2020, time.July, 1, 0, 0, 0, 0, time.UTC)
+	repo := NewTaxRateRepository()
+	err := repo.Load(
+		domain.TaxRate{Jurisdiction: "ca-qc", Category: "consulting", Name: "QST", Rate: "9.975", EffectiveFrom: time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		domain.TaxRate{Jurisdiction: "CA", Category: "consulting", Name: "GST", Rate: "5", EffectiveFrom: time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC), EffectiveUntil: changeDate},
+		domain.TaxRate{Jurisdiction: "CA", Category: "consulting", Name: "GST", Rate: "6", EffectiveFrom: changeDate},
+		domain.TaxRate{Jurisdiction: "CA", Category: "training", Name: "GST", Rate: "0", EffectiveFrom: changeDate},
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	tests := []struct {
+		name          string
+		jurisdiction  string
+		date          time.Time
+		expectedRates []string
+	}{
+		{name: "Subdivision before the change", jurisdiction: "CA-QC", date: changeDate.Add(-time.Second), expectedRates: []string{"CA GST 5", "CA-QC QST 9.975"}},
+		{name: "Subdivision after the change", jurisdiction: "CA-QC", date: changeDate, expectedRates: []string{"CA GST 6", "CA-QC QST 9.975"}},
+		{name: "Other subdivision", jurisdiction: "CA-ON", date: changeDate, expectedRates: []string{"CA GST 6"}},
+		{name: "Other country", jurisdiction: "PT", date: changeDate, expectedRates: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates, err := repo.Find(context.Background(), tt.jurisdiction, "consulting", tt.date)
+			if assert.NoError(t, err) {
+				descriptions := make([]string, 0)
+				for _, rate := range rates {
+					descriptions = append(descriptions, rate.Jurisdiction+" "+rate.Name+" "+rate.Rate)
+				}
+				assert.Equal(t, tt.expectedRates, descriptions)
+			}
+		})
+	}
+}
+
+func TestTaxRateRepositoryLoadFile(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "tax-rates.json")
+	content := `[{"jurisdiction":"PT","category":"consulting","name":"VAT","rate":"23","effectiveFrom":"2012-01-01T00:00:00Z"}]`
+	if err := ioutil.WriteFile(fileName, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewTaxRateRepository()
+	if assert.NoError(t, repo.LoadFile(fileName)) {
+		rates, err := repo.Find(context.Background(), "PT", "consulting", time.Now())
+		if assert.NoError(t, err) && assert.Len(t, rates, 1) {
+			assert.Equal(t, "23", rates[0].Rate)
+		}
+	}
+
+	invalidFileName := filepath.Join(t.TempDir(), "invalid-tax-rates.json")
+	if err := ioutil.WriteFile(invalidFileName, []byte(`[{"jurisdiction":"PT","rate":"abc"}]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, NewTaxRateRepository().LoadFile(invalidFileName))
+}
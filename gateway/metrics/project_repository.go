@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//repositoryMetricsName labels the metrics of the ProjectRepository operations
+const repositoryMetricsName = "ProjectRepository"
+
+//instrumentedProjectRepository decorates the active ProjectRepository, whichever gateway implements it, recording the latency and the errors of each method
+type instrumentedProjectRepository struct {
+	repository domain.ProjectRepository
+}
+
+func observe(method string, start time.Time, err error) {
+	config.GetMetrics().ObserveRepository(repositoryMetricsName, method, time.Since(start), err)
+}
+
+//Get a Project by ID
+func (repo *instrumentedProjectRepository) Get(ctx context.Context, id string) (*domain.Project, error) {
+	start := time.Now()
+	project, err := repo.repository.Get(ctx, id)
+	observe("Get", start, err)
+	return project, err
+}
+
+//Save a new project
+func (repo *instrumentedProjectRepository) Save(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	start := time.Now()
+	project, err := repo.repository.Save(ctx, project)
+	observe("Save", start, err)
+	return project, err
+}
+
+//Update a project
+func (repo *instrumentedProjectRepository) Update(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	start := time.Now()
+	project, err := repo.repository.Update(ctx, project)
+	observe("Update", start, err)
+	return project, err
+}
+
+//GetAll Project
+func (repo *instrumentedProjectRepository) GetAll(ctx context.Context, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	start := time.Now()
+	projectList, err := repo.repository.GetAll(ctx, lastProjectID, pageSize)
+	observe("GetAll", start, err)
+	return projectList, err
+}
+
+//Delete moves a Project to the trash
+func (repo *instrumentedProjectRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	start := time.Now()
+	err := repo.repository.Delete(ctx, id, deletedBy)
+	observe("Delete", start, err)
+	return err
+}
+
+//GetTrash lists the deleted Projects
+func (repo *instrumentedProjectRepository) GetTrash(ctx context.Context, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	start := time.Now()
+	projectList, err := repo.repository.GetTrash(ctx, lastProjectID, pageSize)
+	observe("GetTrash", start, err)
+	return projectList, err
+}
+
+//Restore takes a Project back from the trash
+func (repo *instrumentedProjectRepository) Restore(ctx context.Context, id string) (*domain.Project, error) {
+	start := time.Now()
+	project, err := repo.repository.Restore(ctx, id)
+	observe("Restore", start, err)
+	return project, err
+}
+
+//Purge permanently removes the Projects deleted before the date
+func (repo *instrumentedProjectRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	start := time.Now()
+	purged, err := repo.repository.Purge(ctx, deletedBefore)
+	observe("Purge", start, err)
+	return purged, err
+}
+
+func decorateProjectRepository(component appcontext.Component) (appcontext.Component, error) {
+	return &instrumentedProjectRepository{repository: component.(domain.ProjectRepository)}, nil
+}
+
+func init() {
+	appcontext.Current.Decorate(appcontext.ProjectRepository, decorateProjectRepository)
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/domain"
+	"github.com/danilovalente/project-api/gateway/memory"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestInstrumentedProjectRepository(t *testing.T) {
+	// Setup
+	registry := prometheus.NewRegistry()
+	metrics := NewPrometheusMetrics("project-api", registry)
+	defer appcontext.Current.Override(appcontext.Metrics, metrics)()
+	component, err := decorateProjectRepository(memory.NewProjectRepository())
+	if !assert.NoError(t, err) {
+		return
+	}
+	repo := component.(domain.ProjectRepository)
+	ctx := context.Background()
+
+	saved, err := repo.Save(ctx, &domain.Project{Name: "Project API", UnitPrice: domain.Money{Amount: 10000, Currency: "EUR"}, TimeUnit: "Hour"})
+	assert.NoError(t, err)
+	_, err = repo.Get(ctx, saved.ID.Hex())
+	assert.NoError(t, err)
+	_, err = repo.Get(ctx, primitive.NewObjectID().Hex())
+	assert.Error(t, err)
+
+	// Assertions
+	observed := make(map[string]uint64)
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == "project_api_repository_operation_duration_seconds" {
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "method" {
+						observed[label.GetValue()] = metric.GetHistogram().GetSampleCount()
+					}
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]uint64{"Save": 1, "Get": 2}, observed)
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.repositoryErrors.WithLabelValues(repositoryMetricsName, "Get", "404")))
+}
@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//invalidNameCharacters are replaced from the AppName to build a valid Prometheus namespace
+var invalidNameCharacters = regexp.MustCompile("[^a-zA-Z0-9_]")
+
+//PrometheusMetrics records the domain and repository metrics in the Prometheus default registry, exposed by the /metrics endpoint
+type PrometheusMetrics struct {
+	projectChanges     *prometheus.CounterVec
+	usecaseDuration    *prometheus.HistogramVec
+	repositoryDuration *prometheus.HistogramVec
+	repositoryErrors   *prometheus.CounterVec
+	connectionPool     *prometheus.GaugeVec
+	connectionEvents   *prometheus.CounterVec
+	cacheRequests      *prometheus.CounterVec
+	retries            *prometheus.CounterVec
+	breakerState       *prometheus.GaugeVec
+}
+
+//ProjectChanged counts a Project created, updated or deleted by its currency and time unit
+func (metrics *PrometheusMetrics) ProjectChanged(operation string, currency string, timeUnit string) {
+	metrics.projectChanges.WithLabelValues(operation, currency, timeUnit).Inc()
+}
+
+//ObserveUsecase records the latency and the outcome of a Usecase execution
+func (metrics *PrometheusMetrics) ObserveUsecase(usecase string, duration time.Duration, err error) {
+	metrics.usecaseDuration.WithLabelValues(usecase, outcome(err)).Observe(duration.Seconds())
+}
+
+//ObserveRepository records the latency and the outcome of a Repository method
+func (metrics *PrometheusMetrics) ObserveRepository(repository string, method string, duration time.Duration, err error) {
+	metrics.repositoryDuration.WithLabelValues(repository, method).Observe(duration.Seconds())
+	if err != nil {
+		metrics.repositoryErrors.WithLabelValues(repository, method, outcome(err)).Inc()
+	}
+}
+
+//ConnectionPoolChanged moves the DB connection pool gauge of the provided state by delta
+func (metrics *PrometheusMetrics) ConnectionPoolChanged(state string, delta float64) {
+	metrics.connectionPool.WithLabelValues(state).Add(delta)
+}
+
+//ConnectionPoolEvent counts a DB connection pool event
+func (metrics *PrometheusMetrics) ConnectionPoolEvent(event string) {
+	metrics.connectionEvents.WithLabelValues(event).Inc()
+}
+
+//ObserveCache counts a cache lookup as a hit or a miss
+func (metrics *PrometheusMetrics) ObserveCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	metrics.cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+//ObserveRetry counts a DB operation retried after a transient error
+func (metrics *PrometheusMetrics) ObserveRetry(operation string) {
+	metrics.retries.WithLabelValues(operation).Inc()
+}
+
+//CircuitBreakerChanged sets the gauge of the current state of the breaker to 1 and of the other states to 0
+func (metrics *PrometheusMetrics) CircuitBreakerChanged(breaker string, state string) {
+	for _, breakerState := range []string{"closed", "open", "half-open"} {
+		value := 0.0
+		if breakerState == state {
+			value = 1
+		}
+		metrics.breakerState.WithLabelValues(breaker, breakerState).Set(value)
+	}
+}
+
+//outcome labels a result by the code of the error, as returned to the clients
+func outcome(err error) string {
+	if err == nil {
+		return "success"
+	}
+	if identifiableError, ok := err.(domain.IdentifiableError); ok {
+		return strconv.Itoa(identifiableError.GetCode())
+	}
+	return "error"
+}
+
+//register the collector, reusing the one already registered with the same description
+func register(registerer prometheus.Registerer, collector prometheus.Collector) prometheus.Collector {
+	if err := registerer.Register(collector); err != nil {
+		if alreadyRegistered, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return alreadyRegistered.ExistingCollector
+		}
+		config.GetLogger().Errorf("Could not register the metrics collector. Message: %s", err.Error())
+	}
+	return collector
+}
+
+//NewPrometheusMetrics creates the collectors under the namespace and registers them with the registerer
+func NewPrometheusMetrics(namespace string, registerer prometheus.Registerer) *PrometheusMetrics {
+	namespace = invalidNameCharacters.ReplaceAllString(namespace, "_")
+	return &PrometheusMetrics{
+		projectChanges: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "projects_changed_total",
+			Help:      "Projects created, updated and deleted by currency and time unit",
+		}, []string{"operation", "currency", "time_unit"})).(*prometheus.CounterVec),
+		usecaseDuration: register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "usecase_duration_seconds",
+			Help:      "Latency of the Usecase executions by outcome",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"usecase", "outcome"})).(*prometheus.HistogramVec),
+		repositoryDuration: register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "Latency of the Repository operations by method",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"repository", "method"})).(*prometheus.HistogramVec),
+		repositoryErrors: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_errors_total",
+			Help:      "Failed Repository operations by method and error code",
+		}, []string{"repository", "method", "code"})).(*prometheus.CounterVec),
+		connectionPool: register(registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_connection_pool_connections",
+			Help:      "DB connections in the pool by state",
+		}, []string{"state"})).(*prometheus.GaugeVec),
+		connectionEvents: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_connection_pool_events_total",
+			Help:      "DB connection pool events, like checkout failures and pool clears",
+		}, []string{"event"})).(*prometheus.CounterVec),
+		cacheRequests: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_requests_total",
+			Help:      "Cache lookups by result, hit or miss",
+		}, []string{"cache", "result"})).(*prometheus.CounterVec),
+		retries: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_operation_retries_total",
+			Help:      "DB operations retried after a transient error",
+		}, []string{"operation"})).(*prometheus.CounterVec),
+		breakerState: register(registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_circuit_breaker_state",
+			Help:      "State of the DB circuit breaker, 1 for the current state",
+		}, []string{"breaker", "state"})).(*prometheus.GaugeVec),
+	}
+}
+
+func buildPrometheusMetrics() appcontext.Component {
+	return NewPrometheusMetrics(config.Values.AppName, prometheus.DefaultRegisterer)
+}
+
+func init() {
+	appcontext.Current.Add(appcontext.Metrics, buildPrometheusMetrics, appcontext.Logger)
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	// Setup
+	registry := prometheus.NewRegistry()
+	metrics := NewPrometheusMetrics("project-api", registry)
+
+	metrics.ProjectChanged("created", "EUR", "Hour")
+	metrics.ProjectChanged("created", "EUR", "Hour")
+	metrics.ObserveUsecase("ProjectCreate", time.Millisecond, nil)
+	metrics.ObserveRepository("ProjectRepository", "Get", time.Millisecond, domain.NotFound("Not found"))
+	metrics.ConnectionPoolChanged("open", 2)
+	metrics.ConnectionPoolChanged("open", -1)
+	metrics.ObserveCache("ProjectRepository", true)
+	metrics.ObserveRetry("Get")
+	metrics.CircuitBreakerChanged("mongodb", "open")
+	metrics.ObserveCache("ProjectRepository", false)
+	metrics.ObserveCache("ProjectRepository", true)
+
+	// Assertions
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.projectChanges.WithLabelValues("created", "EUR", "Hour")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.repositoryErrors.WithLabelValues("ProjectRepository", "Get", "404")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.connectionPool.WithLabelValues("open")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.cacheRequests.WithLabelValues("ProjectRepository", "hit")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.cacheRequests.WithLabelValues("ProjectRepository", "miss")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.retries.WithLabelValues("Get")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.breakerState.WithLabelValues("mongodb", "open")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.breakerState.WithLabelValues("mongodb", "closed")))
+	families, err := registry.Gather()
+	if assert.NoError(t, err) {
+		names := make([]string, 0, len(families))
+		for _, family := range families {
+			names = append(names, family.GetName())
+		}
+		assert.Contains(t, names, "project_api_projects_changed_total")
+		assert.Contains(t, names, "project_api_usecase_duration_seconds")
+		assert.Contains(t, names, "project_api_repository_operation_duration_seconds")
+	}
+}
+
+func TestPrometheusMetricsRegisteredTwice(t *testing.T) {
+	// Setup
+	registry := prometheus.NewRegistry()
+	first := NewPrometheusMetrics("project-api", registry)
+	second := NewPrometheusMetrics("project-api", registry)
+
+	second.ProjectChanged("deleted", "USD", "Day")
+
+	// Assertions
+	assert.Equal(t, float64(1), testutil.ToFloat64(first.projectChanges.WithLabelValues("deleted", "USD", "Day")))
+}
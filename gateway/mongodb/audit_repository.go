@@ -0,0 +1,85 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//auditEntryCollectionName in MongoDB
+const auditEntryCollectionName = "auditEntry"
+
+//AuditRepository reads the AuditEntry recorded by the ProjectRepository
+type AuditRepository struct {
+	Conn *mongo.Client
+	//Policy retries the transient errors and fails fast while the database is down. Optional
+	Policy *Policy
+}
+
+//Find the entries matching the query ordered by ID, starting after the LastEntryID
+func (repo *AuditRepository) Find(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	collection := repo.Conn.Database(DatabaseName).Collection(auditEntryCollectionName)
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	filter := bson.M{}
+	for field, value := range map[string]string{"entity": query.Entity, "entityId": query.EntityID, "actor": query.Actor} {
+		if value != "" {
+			filter[field] = value
+		}
+	}
+	occurredAt := bson.M{}
+	if !query.From.IsZero() {
+		occurredAt["$gte"] = query.From
+	}
+	if !query.Until.IsZero() {
+		occurredAt["$lt"] = query.Until
+	}
+	if len(occurredAt) > 0 {
+		filter["occurredAt"] = occurredAt
+	}
+	if strings.TrimSpace(query.LastEntryID) != "" {
+		lastEntry, err := primitive.ObjectIDFromHex(query.LastEntryID)
+		if err != nil {
+			return nil, domain.FieldViolation("lastEntryId", domain.ViolationFormat, "lastEntryId", query.LastEntryID)
+		}
+		filter["_id"] = bson.M{"$gt": lastEntry}
+	}
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(query.PageSize)
+	var entries []*domain.AuditEntry
+	err := repo.Policy.Run(ctx, "FindAuditEntries", func(ctx context.Context) error {
+		entries = make([]*domain.AuditEntry, 0)
+		cur, err := collection.Find(ctx, filter, opts)
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &entries)
+	})
+	if isServiceUnavailable(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("An error occurred while trying to find the audit entries. Message: %s", err.Error()))
+	}
+	return entries, nil
+}
+
+func buildAuditRepository() appcontext.Component {
+	dbClient := appcontext.Current.Get(appcontext.DBClient).(*MongoClient)
+	breaker := appcontext.Current.Get(appcontext.DBCircuitBreaker).(*CircuitBreaker)
+	return &AuditRepository{Conn: dbClient.Conn, Policy: NewPolicy(breaker)}
+}
+
+func init() {
+	if config.Values.Repository != config.RepositoryMongoDB {
+		return
+	}
+	appcontext.Current.AddForProfiles(profiles, appcontext.AuditRepository, buildAuditRepository, appcontext.DBClient, appcontext.DBCircuitBreaker)
+}
@@ -0,0 +1,135 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+)
+
+//List of consts containing the states of the CircuitBreaker
+const (
+	//BreakerClosed - the operations run normally
+	BreakerClosed = "closed"
+	//BreakerOpen - the operations fail fast, as the database is considered down
+	BreakerOpen = "open"
+	//BreakerHalfOpen - a single trial operation checks if the database is back
+	BreakerHalfOpen = "half-open"
+)
+
+//breakerMetricsName labels the state metrics of the DB circuit breaker
+const breakerMetricsName = "mongodb"
+
+//CircuitBreaker opens after consecutive DB unavailability failures, failing fast until the open timeout elapses.
+//Then a single trial operation closes it again on success or keeps it open on failure
+type CircuitBreaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	now              func() time.Time
+
+	mutex    sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+//NewCircuitBreaker creates a closed CircuitBreaker
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	breaker := &CircuitBreaker{failureThreshold: failureThreshold, openTimeout: openTimeout, now: time.Now, state: BreakerClosed}
+	config.GetMetrics().CircuitBreakerChanged(breakerMetricsName, BreakerClosed)
+	return breaker
+}
+
+//allow reports if an operation may run, letting a single trial operation run once the open timeout elapses
+func (breaker *CircuitBreaker) allow() bool {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	switch breaker.state {
+	case BreakerOpen:
+		if breaker.now().Sub(breaker.openedAt) < breaker.openTimeout {
+			return false
+		}
+		breaker.setState(BreakerHalfOpen)
+		breaker.trial = true
+		return true
+	case BreakerHalfOpen:
+		if breaker.trial {
+			return false
+		}
+		breaker.trial = true
+		return true
+	default:
+		return true
+	}
+}
+
+//succeeded closes the breaker, resetting the failures
+func (breaker *CircuitBreaker) succeeded() {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	breaker.failures = 0
+	breaker.trial = false
+	breaker.setState(BreakerClosed)
+}
+
+//failed counts a DB unavailability failure, opening the breaker once the threshold is reached or when the trial operation fails
+func (breaker *CircuitBreaker) failed() {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	breaker.failures++
+	breaker.trial = false
+	if breaker.state == BreakerHalfOpen || breaker.failures >= breaker.failureThreshold {
+		breaker.openedAt = breaker.now()
+		breaker.setState(BreakerOpen)
+	}
+}
+
+//released frees the trial of an operation which neither succeeded nor failed (e.g. canceled by the client)
+func (breaker *CircuitBreaker) released() {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	breaker.trial = false
+}
+
+//setState must be called holding the mutex
+func (breaker *CircuitBreaker) setState(state string) {
+	if breaker.state != state {
+		breaker.state = state
+		config.GetMetrics().CircuitBreakerChanged(breakerMetricsName, state)
+	}
+}
+
+//State returns the current state of the breaker - closed, open or half-open
+func (breaker *CircuitBreaker) State() string {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	return breaker.state
+}
+
+//CheckHealth reports the database as down while the breaker is open
+func (breaker *CircuitBreaker) CheckHealth(ctx context.Context) error {
+	if breaker.State() == BreakerOpen {
+		return errors.New("The circuit breaker is open after consecutive DB failures")
+	}
+	return nil
+}
+
+func buildCircuitBreaker() appcontext.Component {
+	return NewCircuitBreaker(config.Values.DBBreakerFailureThreshold, config.Values.DBBreakerOpenTimeout)
+}
+
+func init() {
+	if config.Values.Repository != config.RepositoryMongoDB {
+		return
+	}
+	appcontext.Current.AddForProfiles(profiles, appcontext.DBCircuitBreaker, buildCircuitBreaker)
+}
@@ -0,0 +1,61 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	breaker := NewCircuitBreaker(3, time.Minute)
+	for i := 0; i < 2; i++ {
+		assert.True(t, breaker.allow())
+		breaker.failed()
+	}
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	//A success resets the consecutive failures
+	breaker.succeeded()
+	for i := 0; i < 3; i++ {
+		assert.True(t, breaker.allow())
+		breaker.failed()
+	}
+	assert.Equal(t, BreakerOpen, breaker.State())
+	assert.False(t, breaker.allow())
+	assert.Error(t, breaker.CheckHealth(context.Background()))
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name          string
+		trialFails    bool
+		expectedState string
+	}{
+		{name: "Trial Succeeds", trialFails: false, expectedState: BreakerClosed},
+		{name: "Trial Fails", trialFails: true, expectedState: BreakerOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			breaker := NewCircuitBreaker(1, time.Minute)
+			breaker.now = func() time.Time { return now }
+			breaker.allow()
+			breaker.failed()
+
+			now = now.Add(time.Minute)
+			assert.True(t, breaker.allow(), "The trial operation should run after the open timeout")
+			assert.Equal(t, BreakerHalfOpen, breaker.State())
+			assert.False(t, breaker.allow(), "A single trial operation should run while half-open")
+			assert.NoError(t, breaker.CheckHealth(context.Background()))
+
+			if tt.trialFails {
+				breaker.failed()
+			} else {
+				breaker.succeeded()
+			}
+			assert.Equal(t, tt.expectedState, breaker.State())
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//tracerName identifies the instrumentation scope of the MongoDB command spans
+const tracerName = "github.com/danilovalente/project-api/gateway/mongodb"
+
+//commandSpanKey identifies a running command, as the driver RequestID is only unique by connection
+type commandSpanKey struct {
+	connectionID string
+	requestID    int64
+}
+
+//commandTracer creates a client span for each command sent to MongoDB, as a child of the span carried by the operation context
+type commandTracer struct {
+	spans sync.Map
+}
+
+func (tracer *commandTracer) started(ctx context.Context, evt *event.CommandStartedEvent) {
+	attributes := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMongoDB,
+			semconv.DBNamespace(evt.DatabaseName),
+			semconv.DBOperationName(evt.CommandName),
+		),
+	}
+	if collectionName, ok := evt.Command.Index(0).Value().StringValueOK(); ok {
+		attributes = append(attributes, trace.WithAttributes(semconv.DBCollectionName(collectionName)))
+	}
+	_, span := otel.Tracer(tracerName).Start(ctx, evt.CommandName, attributes...)
+	tracer.spans.Store(commandSpanKey{connectionID: evt.ConnectionID, requestID: evt.RequestID}, span)
+}
+
+func (tracer *commandTracer) succeeded(_ context.Context, evt *event.CommandSucceededEvent) {
+	if span, ok := tracer.finish(evt.CommandFinishedEvent); ok {
+		span.End()
+	}
+}
+
+func (tracer *commandTracer) failed(_ context.Context, evt *event.CommandFailedEvent) {
+	if span, ok := tracer.finish(evt.CommandFinishedEvent); ok {
+		span.SetStatus(codes.Error, evt.Failure)
+		span.End()
+	}
+}
+
+func (tracer *commandTracer) finish(evt event.CommandFinishedEvent) (trace.Span, bool) {
+	span, ok := tracer.spans.LoadAndDelete(commandSpanKey{connectionID: evt.ConnectionID, requestID: evt.RequestID})
+	if !ok {
+		return nil, false
+	}
+	return span.(trace.Span), true
+}
+
+//newCommandMonitor builds the driver CommandMonitor which traces the MongoDB commands
+func newCommandMonitor() *event.CommandMonitor {
+	tracer := &commandTracer{}
+	return &event.CommandMonitor{
+		Started:   tracer.started,
+		Succeeded: tracer.succeeded,
+		Failed:    tracer.failed,
+	}
+}
@@ -5,14 +5,15 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"fmt"
 	"io/ioutil"
-	"log"
 
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"github.com/danilovalente/project-api/appcontext"
-	"github.com/danilovalente/project-api/config"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 //DatabaseName in MongoDB
@@ -22,6 +23,28 @@ const DatabaseName = "project"
 type MongoClient struct {
 	DBURI string
 	Conn  *mongo.Client
+	//Transactions is false on a standalone mongod, which does not support them. Detected when connecting
+	Transactions bool
+}
+
+//deployment is the part of the isMaster response describing the deployment
+type deployment struct {
+	SetName string `bson:"setName"`
+	Msg     string `bson:"msg"`
+}
+
+//supportsTransactions checks if the deployment is a replica set or a sharded cluster, as the standalone mongod does not support transactions
+func supportsTransactions(ctx context.Context, client *mongo.Client) (bool, error) {
+	var result deployment
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&result); err != nil {
+		return false, err
+	}
+	return result.SetName != "" || result.Msg == "isdbgrid", nil
+}
+
+//withDBTimeout derives the context for a DB operation from the request context, bounded by the configured DBTimeout
+func withDBTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, config.Values.DBTimeout)
 }
 
 func getCustomTLSConfig(caFile string) (*tls.Config, error) {
@@ -43,56 +66,68 @@ func getCustomTLSConfig(caFile string) (*tls.Config, error) {
 }
 
 //buildMongoClientWithTLS creates the MongoClient instance with TLS encryption
-func buildMongoClientWithTLS() appcontext.Component {
-	var mongoClient = MongoClient{}
-
+func buildMongoClientWithTLS() (appcontext.Component, error) {
 	caFilePath := config.Values.DBConnectionCertificateFileName
 
 	tlsConfig, err := getCustomTLSConfig(caFilePath)
 	if err != nil {
-		log.Fatalf("Failed getting TLS configuration: %v", err)
+		return nil, fmt.Errorf("Failed getting TLS configuration: %w", err)
 	}
 
-	mongoClient.DBURI = config.Values.DBConnectionString
-	client, err := mongo.NewClient(options.Client().ApplyURI(mongoClient.DBURI).SetTLSConfig(tlsConfig))
-	if err != nil {
-		log.Fatal("An error occurred while trying to open a DB connection.  Error message: " + err.Error())
-	}
-	ctx := context.Background()
-	err = client.Connect(ctx)
-	if err != nil {
-		log.Fatal("An error occurred while trying to open a DB connection. Error message: " + err.Error())
-	}
-
-	err = client.Ping(ctx, nil)
-	if err != nil {
-		log.Fatalf("Failed to ping cluster: %v", err)
-	}
-	mongoClient.Conn = client
-	return &mongoClient
+	return connect(options.Client().ApplyURI(config.Values.DBConnectionString).SetTLSConfig(tlsConfig))
 }
 
 //buildMongoClient creates the MongoClient instance
-func buildMongoClient() appcontext.Component {
+func buildMongoClient() (appcontext.Component, error) {
+	return connect(options.Client().ApplyURI(config.Values.DBConnectionString))
+}
+
+//connect opens the DB connection, instrumented by the command and pool monitors, and checks it reaches the cluster
+func connect(clientOptions *options.ClientOptions) (*MongoClient, error) {
 	var mongoClient = MongoClient{}
 
 	mongoClient.DBURI = config.Values.DBConnectionString
-	client, err := mongo.NewClient(options.Client().ApplyURI(mongoClient.DBURI))
+	client, err := mongo.NewClient(clientOptions.SetMonitor(newCommandMonitor()).SetPoolMonitor(newPoolMonitor()))
 	if err != nil {
-		log.Fatal("An error occurred while trying to open a DB connection.  Error message: " + err.Error())
+		return nil, errors.New("An error occurred while trying to open a DB connection.  Error message: " + err.Error())
 	}
-	ctx := context.Background()
+	ctx, cancel := withDBTimeout(context.Background())
+	defer cancel()
 	err = client.Connect(ctx)
 	if err != nil {
-		log.Fatal("An error occurred while trying to open a DB connection. Error message: " + err.Error())
+		return nil, errors.New("An error occurred while trying to open a DB connection. Error message: " + err.Error())
 	}
 
 	err = client.Ping(ctx, nil)
 	if err != nil {
-		log.Fatalf("Failed to ping cluster: %v", err)
+		_ = client.Disconnect(ctx)
+		return nil, fmt.Errorf("Failed to ping cluster: %w", err)
+	}
+	mongoClient.Transactions, err = supportsTransactions(ctx, client)
+	if err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, fmt.Errorf("Failed to describe the cluster: %w", err)
+	}
+	if !mongoClient.Transactions {
+		config.GetLogger().Warn("MongoDB is a standalone server, which does not support transactions. The changes of the Projects and their audit entries and versions are written without a transaction. Use a replica set (a single node one is enough) to write them atomically")
 	}
 	mongoClient.Conn = client
-	return &mongoClient
+	return &mongoClient, nil
+}
+
+//Start applies the pending DB migrations, as the connection is opened while the MongoClient is built
+func (client *MongoClient) Start(ctx context.Context) error {
+	return migrate(ctx, client.Conn.Database(DatabaseName))
+}
+
+//Stop closes the connections of the pool, waiting for the operations in use up to the context deadline
+func (client *MongoClient) Stop(ctx context.Context) error {
+	return client.Conn.Disconnect(ctx)
+}
+
+//CheckHealth pings the primary node, reporting whether the DB can serve requests
+func (client *MongoClient) CheckHealth(ctx context.Context) error {
+	return client.Conn.Ping(ctx, readpref.Primary())
 }
 
 //StartTransactionalSession starts a Database Session with an open Transaction
@@ -110,8 +145,9 @@ func (client *MongoClient) StartTransactionalSession() (mongo.Session, error) {
 }
 
 //CollectionExists in the Database?
-func CollectionExists(db *mongo.Database, collectionName string) (bool, error) {
-	ctx := context.Background()
+func CollectionExists(ctx context.Context, db *mongo.Database, collectionName string) (bool, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
 	options := options.ListCollectionsOptions{}
 	options.SetNameOnly(true)
 	cur, err := db.ListCollections(ctx, bson.M{}, &options)
@@ -132,14 +168,16 @@ func CollectionExists(db *mongo.Database, collectionName string) (bool, error) {
 	return false, nil
 }
 
+//profiles in which the MongoDB gateway components are registered
+var profiles = []string{appcontext.ProfileProduction, appcontext.ProfileLocal}
+
 func init() {
-	if config.Values.TestRun {
+	if config.Values.Repository != config.RepositoryMongoDB {
 		return
 	}
-
 	if config.Values.DBConnectionCertificateFileName == "" {
-		appcontext.Current.Add(appcontext.DBClient, buildMongoClient)
+		appcontext.Current.AddFactoryForProfiles(profiles, appcontext.DBClient, buildMongoClient)
 	} else {
-		appcontext.Current.Add(appcontext.DBClient, buildMongoClientWithTLS)
+		appcontext.Current.AddFactoryForProfiles(profiles, appcontext.DBClient, buildMongoClientWithTLS)
 	}
 }
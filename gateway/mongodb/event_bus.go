@@ -0,0 +1,168 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	//eventCollectionName is the capped collection shared by the instances for publishing the ProjectEvent
+	eventCollectionName = "projectEvent"
+	//eventCollectionSize is the maximum size in bytes of the capped collection, replacing the oldest events
+	eventCollectionSize = 1024 * 1024
+	//eventRetryInterval is the time waited before tailing the collection again when the cursor is closed
+	eventRetryInterval = time.Second
+	//eventResumeLookback reads again the events published shortly before the last one received, tolerating the clock skew
+	//between the instances. The handlers must be idempotent, as the events may be delivered more than once
+	eventResumeLookback = time.Minute
+)
+
+//EventBus shares the ProjectEvent between the instances through a capped collection, tailed by each instance
+type EventBus struct {
+	Conn     *mongo.Client
+	handlers []domain.ProjectEventHandler
+	mutex    sync.Mutex
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func (bus *EventBus) collection() *mongo.Collection {
+	return bus.Conn.Database(DatabaseName).Collection(eventCollectionName)
+}
+
+//Publish the event to the subscribers of every instance
+func (bus *EventBus) Publish(ctx context.Context, event domain.ProjectEvent) error {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	if _, err := bus.collection().InsertOne(ctx, event); err != nil {
+		return fmt.Errorf("Could not publish the event %+v. Message: %w", event, err)
+	}
+	return nil
+}
+
+//Subscribe the handler to the events published from now on
+func (bus *EventBus) Subscribe(handler domain.ProjectEventHandler) {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bus.handlers = append(bus.handlers, handler)
+}
+
+//Start creates the capped collection, if it does not exist, and tails it in background
+func (bus *EventBus) Start(ctx context.Context) error {
+	db := bus.Conn.Database(DatabaseName)
+	exists, err := CollectionExists(ctx, db, eventCollectionName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		createCtx, cancel := withDBTimeout(ctx)
+		defer cancel()
+		command := bson.D{{Key: "create", Value: eventCollectionName}, {Key: "capped", Value: true}, {Key: "size", Value: eventCollectionSize}}
+		if err := db.RunCommand(createCtx, command).Err(); err != nil && !isNamespaceExists(err) {
+			return fmt.Errorf("Could not create the %s collection. Message: %w", eventCollectionName, err)
+		}
+	}
+
+	tailCtx, cancel := context.WithCancel(context.Background())
+	bus.cancel = cancel
+	bus.done = make(chan struct{})
+	go bus.tail(tailCtx, time.Now())
+	return nil
+}
+
+//Stop tailing the collection
+func (bus *EventBus) Stop(ctx context.Context) error {
+	if bus.cancel == nil {
+		return nil
+	}
+	bus.cancel()
+	select {
+	case <-bus.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//tail delivers the events published since the provided time, opening the cursor again whenever it is closed
+func (bus *EventBus) tail(ctx context.Context, since time.Time) {
+	defer close(bus.done)
+	logger := config.GetLogger()
+	for {
+		last, err := bus.follow(ctx, since)
+		if !last.IsZero() {
+			since = last.Add(-eventResumeLookback)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Errorf("Could not tail the %s collection. Message: %s", eventCollectionName, err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventRetryInterval):
+		}
+	}
+}
+
+//follow delivers the events of a tailable cursor until it is closed, returning the time of the last event received
+func (bus *EventBus) follow(ctx context.Context, since time.Time) (time.Time, error) {
+	var last time.Time
+	opts := options.Find().SetCursorType(options.TailableAwait)
+	cur, err := bus.collection().Find(ctx, bson.M{"occurredAt": bson.M{"$gte": since}}, opts)
+	if err != nil {
+		return last, err
+	}
+	defer func() { _ = cur.Close(context.Background()) }()
+	for cur.Next(ctx) {
+		var event domain.ProjectEvent
+		if err := cur.Decode(&event); err != nil {
+			return last, err
+		}
+		bus.deliver(event)
+		last = event.OccurredAt
+	}
+	return last, cur.Err()
+}
+
+func (bus *EventBus) deliver(event domain.ProjectEvent) {
+	bus.mutex.Lock()
+	handlers := append([]domain.ProjectEventHandler{}, bus.handlers...)
+	bus.mutex.Unlock()
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+//isNamespaceExists checks if the error reports the collection was created by another instance meanwhile
+func isNamespaceExists(err error) bool {
+	var commandError mongo.CommandError
+	return errors.As(err, &commandError) && commandError.Code == 48
+}
+
+func buildEventBus() (appcontext.Component, error) {
+	dbClient, ok := appcontext.Current.Get(appcontext.DBClient).(*MongoClient)
+	if !ok {
+		return nil, errors.New("The mongodb EventBus requires the mongodb Repository")
+	}
+	return &EventBus{Conn: dbClient.Conn}, nil
+}
+
+func init() {
+	if config.Values.EventBus != config.EventBusMongoDB {
+		return
+	}
+	appcontext.Current.AddFactoryForProfiles(profiles, appcontext.EventBus, buildEventBus, appcontext.DBClient)
+}
@@ -0,0 +1,505 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	//migrationCollectionName records the applied migrations, by version
+	migrationCollectionName = "migrations"
+	//migrationLockCollectionName holds the lock which allows a single instance to run the migrations
+	migrationLockCollectionName = "migrationLock"
+	//migrationLockID identifies the lock document
+	migrationLockID = "migrations"
+	//migrationLockTTL releases the lock of an instance which stopped while running the migrations. The running instance renews it
+	migrationLockTTL = 5 * time.Minute
+	//caseInsensitiveNameIndex was the unique index of the project names, replaced by the projectNameIndex in the migration 9
+	caseInsensitiveNameIndex = "name_unique"
+	//migrationLockRetryInterval is the time waited before trying to acquire the lock held by another instance
+	migrationLockRetryInterval = time.Second
+	//backfillBatchSize is the number of projects a backfill processes under the timeout of a single DB operation
+	backfillBatchSize = 500
+)
+
+//migration is a versioned change of the database, like an index or a backfill of a new field of domain.Project.
+//Each migration runs once and must be idempotent, as an instance may stop before recording it
+type migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+//migrations are applied in the version order. Add the new ones to the end, never changing the applied ones
+var migrations = []migration{
+	{Version: 1, Description: "Create the indexes of the project collection", Up: createProjectIndexes},
+	{Version: 2, Description: "Backfill the dateCreated of the projects from their id", Up: backfillDateCreated},
+	{Version: 3, Description: "Replace the name index of the project collection by a case-insensitive unique one", Up: createProjectNameUniqueIndex},
+	{Version: 4, Description: "Create the index of the taxRate collection", Up: createTaxRateIndex},
+	{Version: 5, Description: "Create the index of the deleted projects", Up: createProjectDeletedAtIndex},
+	{Version: 6, Description: "Create the auditEntry collection and its indexes", Up: createAuditEntryCollection},
+	{Version: 7, Description: "Create the projectVersion collection and its unique index", Up: createProjectVersionCollection},
+	{Version: 8, Description: "Backfill the first version of the projects", Up: backfillProjectVersions},
+	{Version: 9, Description: "Replace the unique index of the project names by one ignoring the deleted projects", Up: createProjectNameDeletedAtUniqueIndex},
+}
+
+//appliedMigration is the record of a migration in the migrations collection
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+//migrate applies the pending migrations holding the lock, waiting while another instance holds it
+func migrate(ctx context.Context, db *mongo.Database) error {
+	runner := &migrationRunner{
+		store:         &mongoMigrationStore{db: db},
+		db:            db,
+		migrations:    migrations,
+		lockTTL:       migrationLockTTL,
+		retryInterval: migrationLockRetryInterval,
+	}
+	return runner.run(ctx)
+}
+
+//errMigrationLockLost reports the lock expired and was taken by another instance while the migrations were applied
+var errMigrationLockLost = errors.New("The DB migrations lock expired and was taken by another instance")
+
+//migrationStore keeps the applied migrations and the lock which allows a single instance to apply them
+type migrationStore interface {
+	//acquireLock takes the lock for the owner until expiresAt if it is free or expired, returning false while another owner holds it
+	acquireLock(ctx context.Context, owner string, expiresAt time.Time) (bool, error)
+	//renewLock extends the lock of the owner until expiresAt, returning false if another owner took it
+	renewLock(ctx context.Context, owner string, expiresAt time.Time) (bool, error)
+	releaseLock(ctx context.Context, owner string) error
+	appliedVersions(ctx context.Context) (map[int]bool, error)
+	recordMigration(ctx context.Context, migration migration) error
+}
+
+//migrationRunner applies the migrations not recorded in the store, renewing its lock every third of the lockTTL while they run
+type migrationRunner struct {
+	store         migrationStore
+	db            *mongo.Database
+	migrations    []migration
+	lockTTL       time.Duration
+	retryInterval time.Duration
+}
+
+func (runner *migrationRunner) run(ctx context.Context) error {
+	owner := primitive.NewObjectID().Hex()
+	if err := runner.acquireLock(ctx, owner); err != nil {
+		return err
+	}
+	defer runner.releaseLock(owner)
+
+	//A lost lock cancels the running migration, so it is not recorded
+	ctx, cancel := context.WithCancel(ctx)
+	renewed := make(chan error, 1)
+	go func() { renewed <- runner.keepLock(ctx, cancel, owner) }()
+	err := runner.applyPending(ctx, owner)
+	cancel()
+	if lockErr := <-renewed; lockErr != nil {
+		return lockErr
+	}
+	return err
+}
+
+func (runner *migrationRunner) applyPending(ctx context.Context, owner string) error {
+	applied, err := runner.store.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+	logger := config.GetLogger()
+	for _, migration := range runner.migrations {
+		if applied[migration.Version] {
+			continue
+		}
+		logger.Infof("Applying the DB migration %d: %s", migration.Version, migration.Description)
+		if err := migration.Up(ctx, runner.db); err != nil {
+			return fmt.Errorf("Could not apply the DB migration %d: %w", migration.Version, err)
+		}
+		//The migration is recorded only if no other instance took the lock while it ran
+		held, err := runner.store.renewLock(ctx, owner, time.Now().Add(runner.lockTTL))
+		if err != nil {
+			return fmt.Errorf("Could not renew the DB migrations lock: %w", err)
+		}
+		if !held {
+			return errMigrationLockLost
+		}
+		if err := runner.store.recordMigration(ctx, migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//acquireLock retries while another instance holds the lock
+func (runner *migrationRunner) acquireLock(ctx context.Context, owner string) error {
+	for {
+		acquired, err := runner.store.acquireLock(ctx, owner, time.Now().Add(runner.lockTTL))
+		if err != nil {
+			return fmt.Errorf("Could not acquire the DB migrations lock: %w", err)
+		}
+		if acquired {
+			return nil
+		}
+		config.GetLogger().Infof("Waiting for the DB migrations run by another instance")
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Could not acquire the DB migrations lock: %w", ctx.Err())
+		case <-time.After(runner.retryInterval):
+		}
+	}
+}
+
+//keepLock renews the lock until the context is done. If another instance took it, or it could not be renewed before expiring,
+//it cancels the migrations and returns errMigrationLockLost
+func (runner *migrationRunner) keepLock(ctx context.Context, cancel context.CancelFunc, owner string) error {
+	ticker := time.NewTicker(runner.lockTTL / 3)
+	defer ticker.Stop()
+	expiresAt := time.Now().Add(runner.lockTTL)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		renewal := time.Now().Add(runner.lockTTL)
+		held, err := runner.store.renewLock(ctx, owner, renewal)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err == nil && held:
+			expiresAt = renewal
+			continue
+		case err != nil && time.Now().Before(expiresAt):
+			config.GetLogger().Warnf("Could not renew the DB migrations lock, which expires at %s. Message: %s", expiresAt.Format(time.RFC3339), err.Error())
+			continue
+		}
+		cancel()
+		return errMigrationLockLost
+	}
+}
+
+//releaseLock deletes the lock if it is still owned by this instance
+func (runner *migrationRunner) releaseLock(owner string) {
+	ctx, cancel := withDBTimeout(context.Background())
+	defer cancel()
+	if err := runner.store.releaseLock(ctx, owner); err != nil {
+		config.GetLogger().Errorf("Could not release the DB migrations lock. It expires in %s. Message: %s", runner.lockTTL, err.Error())
+	}
+}
+
+//mongoMigrationStore keeps the applied migrations in the migrations collection and the lock in the migrationLock one
+type mongoMigrationStore struct {
+	db *mongo.Database
+}
+
+//acquireLock upserts the lock document if it is free or expired. While another instance holds it, the upsert fails with a duplicate key
+func (store *mongoMigrationStore) acquireLock(ctx context.Context, owner string, expiresAt time.Time) (bool, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	filter := bson.M{"_id": migrationLockID, "expiresAt": bson.M{"$lt": time.Now()}}
+	update := bson.M{"$set": bson.M{"owner": owner, "expiresAt": expiresAt}}
+	_, err := store.db.Collection(migrationLockCollectionName).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if isDuplicateKey(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (store *mongoMigrationStore) renewLock(ctx context.Context, owner string, expiresAt time.Time) (bool, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	filter := bson.M{"_id": migrationLockID, "owner": owner}
+	result, err := store.db.Collection(migrationLockCollectionName).UpdateOne(ctx, filter, bson.M{"$set": bson.M{"expiresAt": expiresAt}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (store *mongoMigrationStore) releaseLock(ctx context.Context, owner string) error {
+	_, err := store.db.Collection(migrationLockCollectionName).DeleteOne(ctx, bson.M{"_id": migrationLockID, "owner": owner})
+	return err
+}
+
+func (store *mongoMigrationStore) appliedVersions(ctx context.Context) (map[int]bool, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	cur, err := store.db.Collection(migrationCollectionName).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("Could not find the applied DB migrations: %w", err)
+	}
+	defer func() { _ = cur.Close(ctx) }()
+	applied := make(map[int]bool)
+	for cur.Next(ctx) {
+		var result appliedMigration
+		if err := cur.Decode(&result); err != nil {
+			return nil, fmt.Errorf("Could not decode the applied DB migration: %w", err)
+		}
+		applied[result.Version] = true
+	}
+	return applied, cur.Err()
+}
+
+func (store *mongoMigrationStore) recordMigration(ctx context.Context, migration migration) error {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	record := appliedMigration{Version: migration.Version, Description: migration.Description, AppliedAt: time.Now()}
+	if _, err := store.db.Collection(migrationCollectionName).InsertOne(ctx, record); err != nil {
+		return fmt.Errorf("Could not record the DB migration %d: %w", migration.Version, err)
+	}
+	return nil
+}
+
+//isDuplicateKey checks if the error was caused by a unique index violation
+func isDuplicateKey(err error) bool {
+	var writeException mongo.WriteException
+	if errors.As(err, &writeException) {
+		for _, writeError := range writeException.WriteErrors {
+			if writeError.Code == 11000 {
+				return true
+			}
+		}
+	}
+	var commandError mongo.CommandError
+	return errors.As(err, &commandError) && commandError.Code == 11000
+}
+
+//createProjectIndexes supports filtering and sorting the projects by name, creation date and unit price
+func createProjectIndexes(ctx context.Context, db *mongo.Database) error {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	_, err := db.Collection(projectCollectionName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetName("name")},
+		{Keys: bson.D{{Key: "dateCreated", Value: 1}}, Options: options.Index().SetName("dateCreated")},
+		{Keys: bson.D{{Key: "unitPrice.currency", Value: 1}, {Key: "unitPrice.amount", Value: 1}}, Options: options.Index().SetName("unitPrice")},
+	})
+	return err
+}
+
+//backfillDateCreated sets the dateCreated of the projects created without it to the creation time embedded in their id
+func backfillDateCreated(ctx context.Context, db *mongo.Database) error {
+	exists, err := CollectionExists(ctx, db, projectCollectionName)
+	if err != nil || !exists {
+		return err
+	}
+	collection := db.Collection(projectCollectionName)
+	return inBatches(ctx, func(ctx context.Context, after primitive.ObjectID) (primitive.ObjectID, int, error) {
+		cur, err := findBatch(ctx, collection, bson.M{"dateCreated": bson.M{"$exists": false}}, after, options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return after, 0, err
+		}
+		defer func() { _ = cur.Close(ctx) }()
+		processed := 0
+		for cur.Next(ctx) {
+			var result struct {
+				ID primitive.ObjectID `bson:"_id"`
+			}
+			if err := cur.Decode(&result); err != nil {
+				return after, processed, err
+			}
+			if _, err := collection.UpdateOne(ctx, bson.M{"_id": result.ID}, bson.M{"$set": bson.M{"dateCreated": result.ID.Timestamp()}}); err != nil {
+				return after, processed, err
+			}
+			after = result.ID
+			processed++
+		}
+		return after, processed, cur.Err()
+	})
+}
+
+//backfillBatch processes up to backfillBatchSize projects with an id greater than the after one, returning the id of the last one processed
+//and the number of projects processed
+type backfillBatch func(ctx context.Context, after primitive.ObjectID) (primitive.ObjectID, int, error)
+
+//inBatches runs the batch from the first project until one processes less than backfillBatchSize projects. Each batch has its own DB
+//timeout, so the backfill of a large collection does not have to fit in the timeout of a single operation
+func inBatches(ctx context.Context, batch backfillBatch) error {
+	after := primitive.NilObjectID
+	for {
+		batchCtx, cancel := withDBTimeout(ctx)
+		last, processed, err := batch(batchCtx, after)
+		cancel()
+		if err != nil || processed < backfillBatchSize {
+			return err
+		}
+		after = last
+	}
+}
+
+//findBatch finds, ordered by id, up to backfillBatchSize documents matching the filter with an id greater than the after one
+func findBatch(ctx context.Context, collection *mongo.Collection, filter bson.M, after primitive.ObjectID, findOptions *options.FindOptions) (*mongo.Cursor, error) {
+	batchFilter := bson.M{"$and": bson.A{filter, bson.M{"_id": bson.M{"$gt": after}}}}
+	findOptions.SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(backfillBatchSize)
+	return collection.Find(ctx, batchFilter, findOptions)
+}
+
+//createProjectNameUniqueIndex enforces the case-insensitive uniqueness of the project names. There is no tenant, so the names are unique
+//in the whole collection. It fails listing the duplicate names, which must be renamed before the application starts
+func createProjectNameUniqueIndex(ctx context.Context, db *mongo.Database) error {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	duplicates, err := findDuplicateProjectNames(ctx, db)
+	if err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("The Project names must be unique ignoring the case. Rename the Projects named %s", strings.Join(duplicates, ", "))
+	}
+	indexes := db.Collection(projectCollectionName).Indexes()
+	_, err = indexes.CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetName(caseInsensitiveNameIndex).SetUnique(true).SetCollation(caseInsensitive),
+	})
+	if err != nil {
+		return err
+	}
+	//The unique index also supports filtering and sorting by name
+	if _, err = indexes.DropOne(ctx, "name"); err != nil && !isIndexNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+//findDuplicateProjectNames groups the project names equal under the collation of the unique index, listing each group as "Name / NAME"
+func findDuplicateProjectNames(ctx context.Context, db *mongo.Database) ([]string, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$name"}, {Key: "names", Value: bson.D{{Key: "$push", Value: "$name"}}}}}},
+		{{Key: "$match", Value: bson.D{{Key: "names.1", Value: bson.D{{Key: "$exists", Value: true}}}}}},
+	}
+	cur, err := db.Collection(projectCollectionName).Aggregate(ctx, pipeline, options.Aggregate().SetCollation(caseInsensitive))
+	if err != nil {
+		return nil, err
+	}
+	var groups []struct {
+		Names []string `bson:"names"`
+	}
+	if err := cur.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	duplicates := make([]string, 0, len(groups))
+	for _, group := range groups {
+		duplicates = append(duplicates, strings.Join(group.Names, " / "))
+	}
+	return duplicates, nil
+}
+
+//createTaxRateIndex supports finding the effective rates of a jurisdiction and category, compared ignoring the case as the queries do
+func createTaxRateIndex(ctx context.Context, db *mongo.Database) error {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	_, err := db.Collection(taxRateCollectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "jurisdiction", Value: 1}, {Key: "category", Value: 1}, {Key: "effectiveFrom", Value: 1}},
+		Options: options.Index().SetName("jurisdiction_category_effectiveFrom").SetCollation(caseInsensitive),
+	})
+	return err
+}
+
+//createProjectDeletedAtIndex supports listing the trash and purging the projects deleted before a date. It is sparse, as few projects are deleted
+func createProjectDeletedAtIndex(ctx context.Context, db *mongo.Database) error {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	_, err := db.Collection(projectCollectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "deletedAt", Value: 1}},
+		Options: options.Index().SetName("deletedAt").SetSparse(true),
+	})
+	return err
+}
+
+//createAuditEntryCollection creates the indexes supporting the history of an entity and the search by actor and date. Creating them
+//also creates the collection, which the transactions writing the entries cannot create on the servers before 4.4
+func createAuditEntryCollection(ctx context.Context, db *mongo.Database) error {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	_, err := db.Collection(auditEntryCollectionName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "entity", Value: 1}, {Key: "entityId", Value: 1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("entity_entityId")},
+		{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "occurredAt", Value: 1}}, Options: options.Index().SetName("actor_occurredAt")},
+		{Keys: bson.D{{Key: "occurredAt", Value: 1}}, Options: options.Index().SetName("occurredAt")},
+	})
+	return err
+}
+
+//createProjectVersionCollection creates the unique index of the version numbers of each project, which also supports finding the version
+//current at a date. Creating it also creates the collection, as the auditEntry one
+func createProjectVersionCollection(ctx context.Context, db *mongo.Database) error {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	_, err := db.Collection(projectVersionCollectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "projectId", Value: 1}, {Key: "number", Value: 1}},
+		Options: options.Index().SetName("projectId_number").SetUnique(true),
+	})
+	return err
+}
+
+//backfillProjectVersions records the current state of the projects created before the versions as their first version, dated by their last change
+func backfillProjectVersions(ctx context.Context, db *mongo.Database) error {
+	exists, err := CollectionExists(ctx, db, projectCollectionName)
+	if err != nil || !exists {
+		return err
+	}
+	projects := db.Collection(projectCollectionName)
+	versions := db.Collection(projectVersionCollectionName)
+	return inBatches(ctx, func(ctx context.Context, after primitive.ObjectID) (primitive.ObjectID, int, error) {
+		cur, err := findBatch(ctx, projects, bson.M{}, after, options.Find())
+		if err != nil {
+			return after, 0, err
+		}
+		defer func() { _ = cur.Close(ctx) }()
+		processed := 0
+		for cur.Next(ctx) {
+			var project domain.Project
+			if err := cur.Decode(&project); err != nil {
+				return after, processed, err
+			}
+			version := projectVersionDocument{
+				ID:             primitive.NewObjectID(),
+				ProjectID:      project.ID,
+				ProjectVersion: domain.NewProjectVersion(ctx, 1, &project, project.LastChanged()),
+			}
+			if _, err := versions.InsertOne(ctx, version); err != nil && !isDuplicateKey(err) {
+				return after, processed, err
+			}
+			after = project.ID
+			processed++
+		}
+		return after, processed, cur.Err()
+	})
+}
+
+//createProjectNameDeletedAtUniqueIndex lets the deleted projects release their names. The projects not deleted, without the deletedAt field,
+//are indexed with a null deletedAt, so their names stay unique, while the deleted ones differ by the time they were deleted.
+//MongoDB does not support the partial indexes of the missing fields, which the SQL Repository uses instead
+func createProjectNameDeletedAtUniqueIndex(ctx context.Context, db *mongo.Database) error {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	indexes := db.Collection(projectCollectionName).Indexes()
+	_, err := indexes.CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}, {Key: "deletedAt", Value: 1}},
+		Options: options.Index().SetName(projectNameIndex).SetUnique(true).SetCollation(caseInsensitive),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = indexes.DropOne(ctx, caseInsensitiveNameIndex); err != nil && !isIndexNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+//isIndexNotFound checks if the error reports the index to drop does not exist
+func isIndexNotFound(err error) bool {
+	var commandError mongo.CommandError
+	return errors.As(err, &commandError) && commandError.Code == 27
+}
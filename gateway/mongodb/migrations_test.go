@@ -0,0 +1,244 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func init() {
+	config.UseProfile(appcontext.ProfileTest)
+}
+
+func TestMigrationsAreOrdered(t *testing.T) {
+	for index, migration := range migrations {
+		if migration.Up == nil || migration.Description == "" {
+			t.Errorf("The migration %d must have a description and an Up function", migration.Version)
+		}
+		if index > 0 && migration.Version <= migrations[index-1].Version {
+			t.Errorf("The migration %d must have a version greater than %d", migration.Version, migrations[index-1].Version)
+		}
+	}
+}
+
+//fakeMigrationStore keeps the applied migrations and the lock in memory
+type fakeMigrationStore struct {
+	mutex     sync.Mutex
+	owner     string
+	expiresAt time.Time
+	renewals  int
+	applied   map[int]bool
+}
+
+func (store *fakeMigrationStore) acquireLock(ctx context.Context, owner string, expiresAt time.Time) (bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.owner != "" && time.Now().Before(store.expiresAt) {
+		return false, nil
+	}
+	store.owner, store.expiresAt = owner, expiresAt
+	return true, nil
+}
+
+func (store *fakeMigrationStore) renewLock(ctx context.Context, owner string, expiresAt time.Time) (bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.owner != owner {
+		return false, nil
+	}
+	store.expiresAt = expiresAt
+	store.renewals++
+	return true, nil
+}
+
+func (store *fakeMigrationStore) releaseLock(ctx context.Context, owner string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.owner == owner {
+		store.owner = ""
+	}
+	return nil
+}
+
+func (store *fakeMigrationStore) appliedVersions(ctx context.Context) (map[int]bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	applied := make(map[int]bool)
+	for version := range store.applied {
+		applied[version] = true
+	}
+	return applied, nil
+}
+
+func (store *fakeMigrationStore) recordMigration(ctx context.Context, migration migration) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.applied[migration.Version] = true
+	return nil
+}
+
+//takeLock simulates another instance holding the lock
+func (store *fakeMigrationStore) takeLock(owner string, expiresAt time.Time) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.owner, store.expiresAt = owner, expiresAt
+}
+
+func (store *fakeMigrationStore) lockOwner() string {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.owner
+}
+
+//newMigrationRunner runs the migrations recording their versions in the order they are applied
+func newMigrationRunner(store *fakeMigrationStore, ran *[]int, migrations ...migration) *migrationRunner {
+	for index := range migrations {
+		up, version := migrations[index].Up, migrations[index].Version
+		migrations[index].Up = func(ctx context.Context, db *mongo.Database) error {
+			*ran = append(*ran, version)
+			if up == nil {
+				return nil
+			}
+			return up(ctx, db)
+		}
+	}
+	return &migrationRunner{store: store, migrations: migrations, lockTTL: 50 * time.Millisecond, retryInterval: 5 * time.Millisecond}
+}
+
+func TestMigrationRunnerSkipsAppliedVersions(t *testing.T) {
+	store := &fakeMigrationStore{applied: map[int]bool{1: true}}
+	var ran []int
+	runner := newMigrationRunner(store, &ran, migration{Version: 1}, migration{Version: 2}, migration{Version: 3})
+
+	assert.NoError(t, runner.run(context.Background()))
+	assert.Equal(t, []int{2, 3}, ran)
+	assert.Equal(t, map[int]bool{1: true, 2: true, 3: true}, store.applied)
+	assert.Empty(t, store.lockOwner(), "The lock must be released")
+
+	//Running again applies nothing
+	ran = nil
+	assert.NoError(t, runner.run(context.Background()))
+	assert.Empty(t, ran)
+}
+
+func TestMigrationRunnerWaitsForTheLock(t *testing.T) {
+	store := &fakeMigrationStore{applied: map[int]bool{}}
+	var ran []int
+	runner := newMigrationRunner(store, &ran, migration{Version: 1})
+
+	//The lock held by another instance is not taken before the context is done
+	store.takeLock("other", time.Now().Add(time.Minute))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := runner.run(ctx)
+	if assert.Error(t, err) {
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	}
+	assert.Empty(t, ran)
+	assert.Equal(t, "other", store.lockOwner())
+
+	//It is acquired once the other instance releases it
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = store.releaseLock(context.Background(), "other")
+	}()
+	assert.NoError(t, runner.run(context.Background()))
+	assert.Equal(t, []int{1}, ran)
+
+	//And when the lock of an instance which stopped expires
+	store.takeLock("stopped", time.Now().Add(-time.Millisecond))
+	ran = nil
+	runner = newMigrationRunner(store, &ran, migration{Version: 1}, migration{Version: 2})
+	assert.NoError(t, runner.run(context.Background()))
+	assert.Equal(t, []int{2}, ran)
+}
+
+func TestMigrationRunnerRenewsTheLock(t *testing.T) {
+	store := &fakeMigrationStore{applied: map[int]bool{}}
+	var ran []int
+	//The migration takes longer than the lock TTL, while another instance tries to take it
+	runner := newMigrationRunner(store, &ran, migration{Version: 1, Up: func(ctx context.Context, db *mongo.Database) error {
+		for deadline := time.Now().Add(200 * time.Millisecond); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if acquired, _ := store.acquireLock(ctx, "other", time.Now().Add(time.Minute)); acquired {
+				return errors.New("The lock expired while the migration was running")
+			}
+		}
+		return nil
+	}})
+
+	assert.NoError(t, runner.run(context.Background()))
+	assert.True(t, store.applied[1])
+	assert.Greater(t, store.renewals, 1)
+}
+
+func TestMigrationRunnerFailsWhenTheLockIsLost(t *testing.T) {
+	store := &fakeMigrationStore{applied: map[int]bool{}}
+	var ran []int
+	//Another instance takes the lock, e.g. after this one was paused beyond the TTL
+	runner := newMigrationRunner(store, &ran, migration{Version: 1, Up: func(ctx context.Context, db *mongo.Database) error {
+		store.takeLock("other", time.Now().Add(time.Minute))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return nil
+		}
+	}})
+
+	err := runner.run(context.Background())
+	assert.True(t, errors.Is(err, errMigrationLockLost), err)
+	assert.False(t, store.applied[1], "The migration must not be recorded")
+	assert.Equal(t, "other", store.lockOwner(), "The lock of the other instance must be kept")
+}
+
+func TestInBatches(t *testing.T) {
+	ids := make([]primitive.ObjectID, 2*backfillBatchSize+1)
+	for index := range ids {
+		ids[index] = primitive.NewObjectIDFromTimestamp(time.Unix(int64(index+1), 0))
+	}
+	var batches []int
+	var deadlines []time.Time
+	err := inBatches(context.Background(), func(ctx context.Context, after primitive.ObjectID) (primitive.ObjectID, int, error) {
+		deadline, _ := ctx.Deadline()
+		deadlines = append(deadlines, deadline)
+		first := sort.Search(len(ids), func(index int) bool { return bytes.Compare(ids[index][:], after[:]) > 0 })
+		last := first + backfillBatchSize
+		if last > len(ids) {
+			last = len(ids)
+		}
+		batches = append(batches, first)
+		if first == last {
+			return after, 0, nil
+		}
+		return ids[last-1], last - first, nil
+	})
+
+	assert.NoError(t, err)
+	//Each batch starts after the last project of the previous one, until one is not full
+	assert.Equal(t, []int{0, backfillBatchSize, 2 * backfillBatchSize}, batches)
+	//Under its own timeout
+	for index := 1; index < len(deadlines); index++ {
+		assert.False(t, deadlines[index].Before(deadlines[index-1]))
+	}
+	assert.WithinDuration(t, time.Now().Add(config.Values.DBTimeout), deadlines[len(deadlines)-1], time.Second)
+}
+
+func TestInBatchesStopsAtTheFirstError(t *testing.T) {
+	calls := 0
+	err := inBatches(context.Background(), func(ctx context.Context, after primitive.ObjectID) (primitive.ObjectID, int, error) {
+		calls++
+		return after, backfillBatchSize, errors.New("The batch failed")
+	})
+	assert.EqualError(t, err, "The batch failed")
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,27 @@
+package mongodb
+
+import (
+	"github.com/danilovalente/project-api/config"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+//newPoolMonitor builds the driver PoolMonitor which keeps the connection pool metrics
+func newPoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			metrics := config.GetMetrics()
+			switch evt.Type {
+			case event.ConnectionCreated:
+				metrics.ConnectionPoolChanged("open", 1)
+			case event.ConnectionClosed:
+				metrics.ConnectionPoolChanged("open", -1)
+			case event.GetSucceeded:
+				metrics.ConnectionPoolChanged("in_use", 1)
+			case event.ConnectionReturned:
+				metrics.ConnectionPoolChanged("in_use", -1)
+			case event.GetFailed, event.PoolCleared:
+				metrics.ConnectionPoolEvent(evt.Type)
+			}
+		},
+	}
+}
@@ -2,14 +2,15 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"time"
 	"strings"
+	"time"
 
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"github.com/danilovalente/project-api/appcontext"
 	"github.com/danilovalente/project-api/config"
 	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -19,23 +20,48 @@ import (
 //CollectionName in MongoDB
 const projectCollectionName = "project"
 
+//projectNameIndex is the unique index of the project names, compared with the caseInsensitive collation, and their deletion time.
+//The names of the projects not deleted are unique, while the deleted ones release them
+const projectNameIndex = "name_deletedAt_unique"
+
+//purgeBatchSize is the number of Projects removed in each transaction of the Purge
+const purgeBatchSize = 500
+
+//notDeleted filters out the projects in the trash, matching the documents without the deletedAt field
+var notDeleted = bson.M{"deletedAt": nil}
+
+//deleted filters the projects in the trash
+var deleted = bson.M{"deletedAt": bson.M{"$ne": nil}}
+
+//caseInsensitive compares the strings ignoring the case (but not the diacritics)
+var caseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
 //ProjectRepository is the specification of the features delivered by a Repository for a Project
 type ProjectRepository struct {
 	Conn *mongo.Client
+	//Policy retries the transient errors and fails fast while the database is down. Optional
+	Policy *Policy
+	//Transactions writes the changes with their AuditEntry and ProjectVersion atomically. It requires a replica set
+	Transactions bool
 }
 
 //Get a Project by ID
-func (repo *ProjectRepository) Get(id string) (*domain.Project, error) {
+func (repo *ProjectRepository) Get(ctx context.Context, id string) (*domain.Project, error) {
 	collection := repo.Conn.Database(DatabaseName).Collection(projectCollectionName)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := withDBTimeout(ctx)
 	defer cancel()
 	projectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, domain.ConstraintViolation(fmt.Sprintf("Invalid Project ID format: %s . Message: %s", id, err.Error()))
+		return nil, domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", id)
 	}
-	filter := bson.M{"_id": projectID}
+	filter := bson.M{"_id": projectID, "deletedAt": nil}
 	var project = domain.Project{}
-	err = collection.FindOne(ctx, filter).Decode(&project)
+	err = repo.Policy.Run(ctx, "Get", func(ctx context.Context) error {
+		return collection.FindOne(ctx, filter).Decode(&project)
+	})
+	if isServiceUnavailable(err) {
+		return nil, err
+	}
 	if err != nil && err.Error() == "mongo: no documents in result" {
 		return nil, domain.NotFound(fmt.Sprintf("Could not find Project with the ID: %s . Message: %s", id, err.Error()))
 	}
@@ -45,24 +71,35 @@ func (repo *ProjectRepository) Get(id string) (*domain.Project, error) {
 	return &project, nil
 }
 
-//Save a new project in the collection
-func (repo *ProjectRepository) Save(project *domain.Project) (*domain.Project, error) {
-	collection := repo.Conn.Database(DatabaseName).Collection(projectCollectionName)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//Save a new project in the collection, recording the AuditEntry in the same transaction
+func (repo *ProjectRepository) Save(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	ctx, cancel := withDBTimeout(ctx)
 	defer cancel()
 
-	logger := config.GetLogger
-	defer logger().Sync()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
 
-	logger().Debugf("\n\n\n Before saving to respository %+v \n", project)
+	logger.Debugf("\n\n\n Before saving to respository %+v \n", project)
 
 	if primitive.NilObjectID != project.ID {
 		return nil, domain.InternalError("The Save method should not be used for updating. Please use Update instead")
 	}
 	project.ID = primitive.NewObjectID()
 	project.DateCreated = time.Now()
+	project.Undelete()
 
-	_, err := collection.InsertOne(ctx, project)
+	err := repo.inTransaction(ctx, "Save", func(ctx mongo.SessionContext) error {
+		if _, err := repo.projects().InsertOne(ctx, project); err != nil {
+			return err
+		}
+		return repo.track(ctx, domain.AuditCreated, nil, project, project.DateCreated)
+	})
+	if isServiceUnavailable(err) {
+		return nil, err
+	}
+	if isDuplicateName(err) {
+		return nil, projectNameAlreadyExists(project)
+	}
 	if err != nil {
 		return nil, domain.InternalError(fmt.Sprintf("Could not create the project. project: %+v - Message: %s", project, err.Error()))
 	}
@@ -70,21 +107,34 @@ func (repo *ProjectRepository) Save(project *domain.Project) (*domain.Project, e
 
 }
 
-//Update a project in the collection
-func (repo *ProjectRepository) Update(project *domain.Project) (*domain.Project, error) {
-	collection := repo.Conn.Database(DatabaseName).Collection(projectCollectionName)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//Update a project in the collection, recording the AuditEntry in the same transaction
+func (repo *ProjectRepository) Update(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	ctx, cancel := withDBTimeout(ctx)
 	defer cancel()
 
-	filter := bson.M{"_id": project.ID}
-	existentProject, err := repo.Get(project.ID.Hex())
-	if err != nil {
+	filter := bson.M{"_id": project.ID, "deletedAt": nil}
+	err := repo.inTransaction(ctx, "Update", func(ctx mongo.SessionContext) error {
+		var existentProject domain.Project
+		if err := repo.projects().FindOne(ctx, filter).Decode(&existentProject); err != nil {
+			return err
+		}
+		project.DateCreated = existentProject.DateCreated
+		project.DateUpdated = time.Now()
+		project.Undelete()
+		if _, err := repo.projects().ReplaceOne(ctx, filter, project); err != nil {
+			return err
+		}
+		return repo.track(ctx, domain.AuditUpdated, &existentProject, project, project.DateUpdated)
+	})
+	if isServiceUnavailable(err) {
 		return nil, err
 	}
-
-	project.DateCreated = existentProject.DateCreated
-	project.DateUpdated = time.Now()
-	_, err = collection.ReplaceOne(ctx, filter, project)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.NotFound(fmt.Sprintf("Could not find Project with the ID: %s", project.ID.Hex()))
+	}
+	if isDuplicateName(err) {
+		return nil, projectNameAlreadyExists(project)
+	}
 	if err != nil {
 		return nil, domain.InternalError(fmt.Sprintf("Could not update the project with ID = %s - Message: %s", project.ID.Hex(), err.Error()))
 	}
@@ -93,72 +143,244 @@ func (repo *ProjectRepository) Update(project *domain.Project) (*domain.Project,
 }
 
 //GetAll Project
-func (repo *ProjectRepository) GetAll(lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+func (repo *ProjectRepository) GetAll(ctx context.Context, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	return repo.list(ctx, "GetAll", notDeleted, lastProjectID, pageSize)
+}
+
+//GetTrash lists the deleted Projects ordered by ID, starting after the lastProjectID
+func (repo *ProjectRepository) GetTrash(ctx context.Context, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	return repo.list(ctx, "GetTrash", deleted, lastProjectID, pageSize)
+}
+
+//list the Projects matching the deletion filter ordered by ID, starting after the lastProjectID
+func (repo *ProjectRepository) list(ctx context.Context, operation string, deletionFilter bson.M, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
 	projectList := make([]*domain.Project, 0)
 	collection := repo.Conn.Database(DatabaseName).Collection(projectCollectionName)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := withDBTimeout(ctx)
 	defer cancel()
-	var dbfilter interface{}
-	if strings.TrimSpace(lastProjectID) == "" {
-		dbfilter = bson.D{}
-	} else {
+	dbfilter := bson.M{}
+	for key, value := range deletionFilter {
+		dbfilter[key] = value
+	}
+	if strings.TrimSpace(lastProjectID) != "" {
 		lastProject, err := primitive.ObjectIDFromHex(lastProjectID)
 		if err != nil {
-			return nil, domain.ConstraintViolation(fmt.Sprintf("Invalid project Id: %s. Message: %s", lastProjectID, err.Error()))
+			return nil, domain.FieldViolation("lastProjectId", domain.ViolationFormat, "lastProjectId", lastProjectID)
 		}
-		dbfilter = bson.M{"_id": bson.M{"$gt": lastProject}}
+		dbfilter["_id"] = bson.M{"$gt": lastProject}
 	}
 	opts := &options.FindOptions{}
 	opts.SetSort(bson.M{"_id": 1})
 	opts.SetLimit(pageSize)
-	cur, err := collection.Find(ctx, dbfilter, opts)
-	defer func() { _ = cur.Close(ctx) }()
-	if err != nil {
-		return nil, domain.InternalError(fmt.Sprintf("An error occurred while trying to find the project List. Message: %s", err.Error()))
-	}
-	for cur.Next(ctx) {
-		var result domain.Project
-		err := cur.Decode(&result)
+	//The whole page is read again by a retry, as the cursor may fail while iterating
+	err := repo.Policy.Run(ctx, operation, func(ctx context.Context) error {
+		projectList = make([]*domain.Project, 0)
+		cur, err := collection.Find(ctx, dbfilter, opts)
 		if err != nil {
-			return nil, domain.InternalError(fmt.Sprintf("An error occured while trying to convert the Project from the database. Message: %s", err.Error()))
+			return err
+		}
+		defer func() { _ = cur.Close(ctx) }()
+		for cur.Next(ctx) {
+			var result domain.Project
+			err := cur.Decode(&result)
+			if err != nil {
+				return domain.InternalError(fmt.Sprintf("An error occured while trying to convert the Project from the database. Message: %s", err.Error()))
+			}
+			projectList = append(projectList, &result)
 		}
-		projectList = append(projectList, &result)
+		return cur.Err()
+	})
+	if _, ok := err.(domain.IdentifiableError); ok {
+		return nil, err
 	}
-	if err := cur.Err(); err != nil {
-		return nil, domain.InternalError(fmt.Sprintf("An error occured while trying to convert the list of Project from the database. Message: %s", err.Error()))
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("An error occurred while trying to find the project List. Message: %s", err.Error()))
 	}
 	return projectList, nil
 }
 
-//Delete a ProjectRepository by ID
-func (repo *ProjectRepository) Delete(id string) error {
-	collection := repo.Conn.Database(DatabaseName).Collection(projectCollectionName)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//Delete moves the Project with the ID to the trash, recording the AuditEntry in the same transaction
+func (repo *ProjectRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	ctx, cancel := withDBTimeout(ctx)
 	defer cancel()
 	projectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return domain.ConstraintViolation(fmt.Sprintf("Invalid Project ID format: %s . Message: %s", id, err.Error()))
+		return domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", id)
 	}
-	filter := bson.M{"_id": projectID}
-	result, err := collection.DeleteOne(ctx, filter)
-	if err != nil {
-		return domain.InternalError(fmt.Sprintf("Database error while deleting the Project with ID: %s - Message: %s", id, err.Error()))
+	filter := bson.M{"_id": projectID, "deletedAt": nil}
+	err = repo.inTransaction(ctx, "Delete", func(ctx mongo.SessionContext) error {
+		var before domain.Project
+		if err := repo.projects().FindOne(ctx, filter).Decode(&before); err != nil {
+			return err
+		}
+		after := before
+		after.DeletedAt = time.Now()
+		after.DeletedBy = deletedBy
+		update := bson.M{"$set": bson.M{"deletedAt": after.DeletedAt, "deletedBy": after.DeletedBy}}
+		if _, err := repo.projects().UpdateOne(ctx, filter, update); err != nil {
+			return err
+		}
+		return repo.track(ctx, domain.AuditDeleted, &before, &after, after.DeletedAt)
+	})
+	if isServiceUnavailable(err) {
+		return err
 	}
-	if result.DeletedCount != 1 {
+	if errors.Is(err, mongo.ErrNoDocuments) {
 		return domain.NotFound(fmt.Sprintf("Could not find Project with the ID: %s", id))
 	}
+	if err != nil {
+		return domain.InternalError(fmt.Sprintf("Database error while deleting the Project with ID: %s - Message: %s", id, err.Error()))
+	}
 	return nil
 }
 
+//Restore takes the deleted Project with the ID back from the trash, recording the AuditEntry in the same transaction
+func (repo *ProjectRepository) Restore(ctx context.Context, id string) (*domain.Project, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	projectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", id)
+	}
+	filter := bson.M{"_id": projectID, "deletedAt": bson.M{"$ne": nil}}
+	update := bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": ""}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	var project = domain.Project{}
+	err = repo.inTransaction(ctx, "Restore", func(ctx mongo.SessionContext) error {
+		var before domain.Project
+		if err := repo.projects().FindOneAndUpdate(ctx, filter, update, opts).Decode(&before); err != nil {
+			return err
+		}
+		project = before
+		project.Undelete()
+		return repo.track(ctx, domain.AuditRestored, &before, &project, time.Now())
+	})
+	if isServiceUnavailable(err) {
+		return nil, err
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.NotFound(fmt.Sprintf("Could not find the deleted Project with the ID: %s", id))
+	}
+	if isDuplicateName(err) {
+		return nil, domain.AlreadyExistsForField("name", fmt.Sprintf("Another Project took the name of the deleted Project with the ID: %s", id))
+	}
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("Database error while restoring the Project with ID: %s - Message: %s", id, err.Error()))
+	}
+	return &project, nil
+}
+
+//Purge permanently removes the Projects deleted before the date, recording an AuditEntry for each one in the same transaction.
+//They are removed in batches of purgeBatchSize Projects, each one in its own transaction and under its own DB timeout, so a large trash does
+//not exceed the limits of a single transaction. A failed batch is reported with the Projects removed by the previous ones.
+//Their previous AuditEntry and ProjectVersion are kept
+func (repo *ProjectRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	purged := int64(0)
+	for {
+		found, batchPurged, err := repo.purgeBatch(ctx, deletedBefore)
+		if isServiceUnavailable(err) {
+			return purged, err
+		}
+		if err != nil {
+			return purged, domain.InternalError(fmt.Sprintf("Database error while purging the Projects deleted before %s - Message: %s", deletedBefore.Format(time.RFC3339), err.Error()))
+		}
+		purged += batchPurged
+		if found < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+//purgeBatch removes up to purgeBatchSize of the Projects deleted before the date in a transaction, returning how many were found and removed
+func (repo *ProjectRepository) purgeBatch(ctx context.Context, deletedBefore time.Time) (int, int64, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	filter := bson.M{"deletedAt": bson.M{"$lt": deletedBefore}}
+	found, purged := 0, int64(0)
+	err := repo.inTransaction(ctx, "Purge", func(ctx mongo.SessionContext) error {
+		cur, err := repo.projects().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(purgeBatchSize))
+		if err != nil {
+			return err
+		}
+		projects := make([]domain.Project, 0)
+		if err := cur.All(ctx, &projects); err != nil {
+			return err
+		}
+		found, purged = len(projects), 0
+		if len(projects) == 0 {
+			return nil
+		}
+		projectIDs := make([]primitive.ObjectID, 0, len(projects))
+		entries := make([]interface{}, 0, len(projects))
+		for index := range projects {
+			projectIDs = append(projectIDs, projects[index].ID)
+			entries = append(entries, domain.NewProjectAuditEntry(ctx, domain.AuditPurged, &projects[index], nil))
+		}
+		result, err := repo.projects().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": projectIDs}, "deletedAt": bson.M{"$lt": deletedBefore}})
+		if err != nil {
+			return err
+		}
+		if _, err := repo.Conn.Database(DatabaseName).Collection(auditEntryCollectionName).InsertMany(ctx, entries); err != nil {
+			return err
+		}
+		purged = result.DeletedCount
+		return nil
+	})
+	return found, purged, err
+}
+
+//projects is the collection of the Projects
+func (repo *ProjectRepository) projects() *mongo.Collection {
+	return repo.Conn.Database(DatabaseName).Collection(projectCollectionName)
+}
+
+//track records the change of the Project, made at the date, in the auditEntry and the projectVersion collections, in the transaction of the change
+func (repo *ProjectRepository) track(ctx mongo.SessionContext, action string, before *domain.Project, after *domain.Project, date time.Time) error {
+	db := repo.Conn.Database(DatabaseName)
+	if _, err := db.Collection(auditEntryCollectionName).InsertOne(ctx, domain.NewProjectAuditEntry(ctx, action, before, after)); err != nil {
+		return err
+	}
+	return recordVersion(ctx, db, after, date)
+}
+
+//inTransaction runs the writes of the operation in a transaction, which requires a replica set. The driver retries the whole
+//transaction while it fails with transient errors, like write conflicts, and the Policy retries the failures to start it.
+//Without Transactions (e.g. on a standalone mongod) the writes run in order in a session, so a failure may leave the first ones applied
+func (repo *ProjectRepository) inTransaction(ctx context.Context, operation string, fn func(ctx mongo.SessionContext) error) error {
+	return repo.Policy.Run(ctx, operation, func(ctx context.Context) error {
+		session, err := repo.Conn.StartSession()
+		if err != nil {
+			return err
+		}
+		defer session.EndSession(ctx)
+		if !repo.Transactions {
+			return mongo.WithSession(ctx, session, fn)
+		}
+		_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+			return nil, fn(ctx)
+		})
+		return err
+	})
+}
+
+//isDuplicateName checks if the error was caused by the unique index of the project names
+func isDuplicateName(err error) bool {
+	return isDuplicateKey(err) && strings.Contains(err.Error(), projectNameIndex)
+}
+
+func projectNameAlreadyExists(project *domain.Project) domain.AlreadyExistsError {
+	return domain.AlreadyExistsForField("name", fmt.Sprintf("A Project named %s already exists", project.Name))
+}
+
 func buildProjectRepository() appcontext.Component {
 	dbClient := appcontext.Current.Get(appcontext.DBClient).(*MongoClient)
-	return &ProjectRepository{Conn: dbClient.Conn}
+	breaker := appcontext.Current.Get(appcontext.DBCircuitBreaker).(*CircuitBreaker)
+	return &ProjectRepository{Conn: dbClient.Conn, Policy: NewPolicy(breaker), Transactions: dbClient.Transactions}
 }
 
 func init() {
-	if config.Values.TestRun {
+	if config.Values.Repository != config.RepositoryMongoDB {
 		return
 	}
-
-	appcontext.Current.Add(appcontext.ProjectRepository, buildProjectRepository)
+	appcontext.Current.AddForProfiles(profiles, appcontext.ProjectRepository, buildProjectRepository, appcontext.DBClient, appcontext.DBCircuitBreaker)
 }
@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//projectVersionCollectionName in MongoDB
+const projectVersionCollectionName = "projectVersion"
+
+//projectVersionDocument stores the ProjectVersion with the ID of its Project, unique with the number
+type projectVersionDocument struct {
+	ID primitive.ObjectID `bson:"_id"`
+
+	ProjectID primitive.ObjectID `bson:"projectId"`
+
+	domain.ProjectVersion `bson:",inline"`
+}
+
+//ProjectVersionRepository reads the ProjectVersion recorded by the ProjectRepository
+type ProjectVersionRepository struct {
+	Conn *mongo.Client
+	//Policy retries the transient errors and fails fast while the database is down. Optional
+	Policy *Policy
+}
+
+//recordVersion inserts the next version of the Project, changed at the date, in the transaction of the change.
+//The transaction also writes the Project, so the concurrent changes conflict instead of taking the same number
+func recordVersion(ctx mongo.SessionContext, db *mongo.Database, project *domain.Project, date time.Time) error {
+	collection := db.Collection(projectVersionCollectionName)
+	var last projectVersionDocument
+	opts := options.FindOne().SetSort(bson.M{"number": -1})
+	err := collection.FindOne(ctx, bson.M{"projectId": project.ID}, opts).Decode(&last)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	_, err = collection.InsertOne(ctx, projectVersionDocument{
+		ID:             primitive.NewObjectID(),
+		ProjectID:      project.ID,
+		ProjectVersion: domain.NewProjectVersion(ctx, last.Number+1, project, date),
+	})
+	return err
+}
+
+//Get the version number of the Project
+func (repo *ProjectVersionRepository) Get(ctx context.Context, projectID string, number int64) (*domain.ProjectVersion, error) {
+	return repo.find(ctx, projectID, fmt.Sprintf("the version %d", number), bson.M{"number": number}, nil)
+}
+
+//GetAsOf gets the version of the Project current at the date, the last one created until then
+func (repo *ProjectVersionRepository) GetAsOf(ctx context.Context, projectID string, asOf time.Time) (*domain.ProjectVersion, error) {
+	return repo.find(ctx, projectID, "a version as of "+asOf.Format(time.RFC3339), bson.M{"dateCreated": bson.M{"$lte": asOf}}, options.FindOne().SetSort(bson.M{"number": -1}))
+}
+
+//find the version of the Project matching the filter, described for the errors
+func (repo *ProjectVersionRepository) find(ctx context.Context, projectID string, description string, filter bson.M, opts *options.FindOneOptions) (*domain.ProjectVersion, error) {
+	collection := repo.Conn.Database(DatabaseName).Collection(projectVersionCollectionName)
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	id, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", projectID)
+	}
+	filter["projectId"] = id
+	if opts == nil {
+		opts = options.FindOne()
+	}
+	var document projectVersionDocument
+	err = repo.Policy.Run(ctx, "GetProjectVersion", func(ctx context.Context) error {
+		return collection.FindOne(ctx, filter, opts).Decode(&document)
+	})
+	if isServiceUnavailable(err) {
+		return nil, err
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.NotFound(fmt.Sprintf("Could not find %s of the Project with the ID: %s", description, projectID))
+	}
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("Database fetch error while Getting %s of the Project with ID: %s - Message: %s", description, projectID, err.Error()))
+	}
+	return &document.ProjectVersion, nil
+}
+
+func buildProjectVersionRepository() appcontext.Component {
+	dbClient := appcontext.Current.Get(appcontext.DBClient).(*MongoClient)
+	breaker := appcontext.Current.Get(appcontext.DBCircuitBreaker).(*CircuitBreaker)
+	return &ProjectVersionRepository{Conn: dbClient.Conn, Policy: NewPolicy(breaker)}
+}
+
+func init() {
+	if config.Values.Repository != config.RepositoryMongoDB {
+		return
+	}
+	appcontext.Current.AddForProfiles(profiles, appcontext.ProjectVersionRepository, buildProjectVersionRepository, appcontext.DBClient, appcontext.DBCircuitBreaker)
+}
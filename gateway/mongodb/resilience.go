@@ -0,0 +1,159 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+//transientErrorCodes are the server error codes of network failures, elections and shutdowns, which may succeed when retried
+var transientErrorCodes = map[int]bool{
+	6:     true, //HostUnreachable
+	7:     true, //HostNotFound
+	89:    true, //NetworkTimeout
+	91:    true, //ShutdownInProgress
+	189:   true, //PrimarySteppedDown
+	262:   true, //ExceededTimeLimit
+	9001:  true, //SocketException
+	10107: true, //NotWritablePrimary
+	11600: true, //InterruptedAtShutdown
+	11602: true, //InterruptedDueToReplStateChange
+	13435: true, //NotPrimaryNoSecondaryOk
+	13436: true, //NotPrimaryOrSecondary
+}
+
+//transientErrorLabels are attached by the server and the driver to the errors which may succeed when retried
+var transientErrorLabels = []string{"NetworkError", "TransientTransactionError", "RetryableWriteError"}
+
+//Policy runs the DB operations retrying the transient errors with jittered exponential backoff, limited by the deadline of the context.
+//The Breaker fails fast while the database is down
+type Policy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Breaker     *CircuitBreaker
+}
+
+//NewPolicy creates the Policy with the configured retries
+func NewPolicy(breaker *CircuitBreaker) *Policy {
+	return &Policy{
+		MaxAttempts: config.Values.DBRetryMaxAttempts,
+		BaseDelay:   config.Values.DBRetryBaseDelay,
+		MaxDelay:    config.Values.DBRetryMaxDelay,
+		Breaker:     breaker,
+	}
+}
+
+//Run the operation. It returns a domain.ServiceUnavailableError while the breaker is open or when the transient errors persist,
+//otherwise the error of the operation
+func (policy *Policy) Run(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	if policy == nil {
+		return fn(ctx)
+	}
+	if policy.Breaker != nil && !policy.Breaker.allow() {
+		return domain.ServiceUnavailable("The database is unavailable. Please try again later")
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn(ctx)
+		if err != nil && ctx.Err() != nil {
+			//The caller's deadline expired or the caller canceled, which tells nothing about the database.
+			//The driver may report it as a network error, so it is neither retried nor counted by the breaker
+			policy.record(ctx.Err())
+			return err
+		}
+		if err == nil || !isTransient(err) || attempt >= policy.MaxAttempts {
+			break
+		}
+		delay := policy.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			break
+		}
+		config.GetMetrics().ObserveRetry(operation)
+		config.GetContextLogger(ctx).Warnf("Retrying the DB operation %s in %s after a transient error. Message: %s", operation, delay, err.Error())
+		select {
+		case <-ctx.Done():
+			policy.record(ctx.Err())
+			return err
+		case <-time.After(delay):
+		}
+	}
+
+	policy.record(err)
+	if isTransient(err) {
+		return domain.ServiceUnavailable("The database is unavailable. Please try again later. Message: " + err.Error())
+	}
+	return err
+}
+
+//record the outcome of the operation in the breaker. Only the DB unavailability counts as a failure
+func (policy *Policy) record(err error) {
+	if policy.Breaker == nil {
+		return
+	}
+	switch {
+	case err == nil:
+		policy.Breaker.succeeded()
+	case isTransient(err):
+		policy.Breaker.failed()
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		policy.Breaker.released()
+	default:
+		//The database answered, e.g. with a duplicate key or no documents
+		policy.Breaker.succeeded()
+	}
+}
+
+//backoff returns a random delay up to the exponential delay of the attempt ("full jitter"), bounded by the MaxDelay
+func (policy *Policy) backoff(attempt int) time.Duration {
+	delay := policy.BaseDelay << uint(attempt-1)
+	if delay <= 0 || delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+//isServiceUnavailable checks if the error was returned by the Policy because the database is down
+func isServiceUnavailable(err error) bool {
+	_, ok := err.(domain.ServiceUnavailableError)
+	return ok
+}
+
+//isTransient checks if the error is caused by the database being unreachable or electing a new primary.
+//The expired deadline or the cancellation of the caller's context is not, even when it interrupts a slow DB operation
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, mongo.ErrClientDisconnected) {
+		return false
+	}
+	var commandError mongo.CommandError
+	if errors.As(err, &commandError) {
+		return transientErrorCodes[int(commandError.Code)] || hasTransientLabel(commandError)
+	}
+	var writeException mongo.WriteException
+	if errors.As(err, &writeException) {
+		return writeException.WriteConcernError != nil && transientErrorCodes[writeException.WriteConcernError.Code]
+	}
+	var connectionError topology.ConnectionError
+	if errors.As(err, &connectionError) {
+		return true
+	}
+	//The driver does not wrap the server selection errors, raised while there is no primary
+	return strings.Contains(err.Error(), "server selection error")
+}
+
+func hasTransientLabel(commandError mongo.CommandError) bool {
+	for _, label := range transientErrorLabels {
+		if commandError.HasErrorLabel(label) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/domain"
+	_ "github.com/danilovalente/project-api/gateway/customlog"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+//primarySteppedDown is returned by the server during a primary election
+var primarySteppedDown = mongo.CommandError{Code: 189, Name: "PrimarySteppedDown", Message: "primary stepped down"}
+
+func newTestPolicy(breaker *CircuitBreaker) *Policy {
+	return &Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, Breaker: breaker}
+}
+
+//failingOperation fails with the errors in order, then succeeds
+func failingOperation(attempts *int, errs ...error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		*attempts++
+		if *attempts <= len(errs) {
+			return errs[*attempts-1]
+		}
+		return nil
+	}
+}
+
+func TestPolicyRetriesTransientErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		errs             []error
+		expectedAttempts int
+		expectedCode     int
+		expectedErr      error
+	}{
+		{name: "Recovered", errs: []error{primarySteppedDown, primarySteppedDown}, expectedAttempts: 3},
+		{name: "Exhausted", errs: []error{primarySteppedDown, primarySteppedDown, primarySteppedDown}, expectedAttempts: 3, expectedCode: 503},
+		{name: "Not Transient", errs: []error{mongo.ErrNoDocuments}, expectedAttempts: 1, expectedErr: mongo.ErrNoDocuments},
+		{name: "Server Selection", errs: []error{errors.New("server selection error: server selection timeout")}, expectedAttempts: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := newTestPolicy(nil).Run(context.Background(), "Get", failingOperation(&attempts, tt.errs...))
+			assert.Equal(t, tt.expectedAttempts, attempts)
+			if tt.expectedCode != 0 {
+				if identifiableError, ok := err.(domain.IdentifiableError); assert.True(t, ok) {
+					assert.Equal(t, tt.expectedCode, identifiableError.GetCode())
+				}
+			} else {
+				assert.Equal(t, tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestPolicyStopsAtTheDeadline(t *testing.T) {
+	policy := newTestPolicy(nil)
+	policy.BaseDelay = time.Hour
+	policy.MaxDelay = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	attempts := 0
+	start := time.Now()
+	err := policy.Run(ctx, "Get", failingOperation(&attempts, primarySteppedDown, primarySteppedDown))
+	assert.True(t, isServiceUnavailable(err))
+	assert.Less(t, time.Since(start), time.Second)
+	assert.LessOrEqual(t, attempts, 2)
+}
+
+func TestPolicyFailsFastWhileTheBreakerIsOpen(t *testing.T) {
+	breaker := NewCircuitBreaker(1, time.Minute)
+	policy := newTestPolicy(breaker)
+
+	attempts := 0
+	_ = policy.Run(context.Background(), "Get", failingOperation(&attempts, primarySteppedDown, primarySteppedDown, primarySteppedDown))
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	attempts = 0
+	err := policy.Run(context.Background(), "Get", failingOperation(&attempts))
+	assert.True(t, isServiceUnavailable(err))
+	assert.Equal(t, 0, attempts)
+}
+
+func TestPolicyDoesNotCountAnsweredErrors(t *testing.T) {
+	breaker := NewCircuitBreaker(1, time.Minute)
+	policy := newTestPolicy(breaker)
+
+	attempts := 0
+	_ = policy.Run(context.Background(), "Get", failingOperation(&attempts, mongo.ErrNoDocuments))
+	_ = policy.Run(context.Background(), "Get", failingOperation(&attempts, context.Canceled))
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
+
+func TestPolicyDoesNotCountTheCallerDeadline(t *testing.T) {
+	breaker := NewCircuitBreaker(1, time.Minute)
+	policy := newTestPolicy(breaker)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+
+	for _, operationErr := range []error{context.DeadlineExceeded, topology.ConnectionError{Wrapped: context.DeadlineExceeded}} {
+		attempts := 0
+		err := policy.Run(ctx, "Get", failingOperation(&attempts, operationErr))
+		assert.Equal(t, 1, attempts)
+		assert.False(t, isServiceUnavailable(err))
+		assert.Equal(t, BreakerClosed, breaker.State())
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, isTransient(primarySteppedDown))
+	assert.True(t, isTransient(mongo.CommandError{Code: 2, Labels: []string{"RetryableWriteError"}}))
+	assert.True(t, isTransient(topology.ConnectionError{Wrapped: errors.New("i/o timeout")}))
+	assert.False(t, isTransient(context.DeadlineExceeded))
+	assert.False(t, isTransient(context.Canceled))
+	assert.False(t, isTransient(mongo.ErrNoDocuments))
+}
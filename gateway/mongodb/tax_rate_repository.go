@@ -0,0 +1,70 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//taxRateCollectionName in MongoDB
+const taxRateCollectionName = "taxRate"
+
+//TaxRateRepository reads the TaxRates from MongoDB
+type TaxRateRepository struct {
+	Conn *mongo.Client
+	//Policy retries the transient errors and fails fast while the database is down. Optional
+	Policy *Policy
+}
+
+//Find the rates charged in the jurisdiction (including the ones of its country) for the category at the date, ordered by jurisdiction and name
+func (repo *TaxRateRepository) Find(ctx context.Context, jurisdiction string, category string, date time.Time) ([]domain.TaxRate, error) {
+	collection := repo.Conn.Database(DatabaseName).Collection(taxRateCollectionName)
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	filter := bson.M{
+		"jurisdiction":  bson.M{"$in": domain.Jurisdictions(jurisdiction)},
+		"category":      category,
+		"effectiveFrom": bson.M{"$lte": date},
+		"$or": bson.A{
+			bson.M{"effectiveUntil": nil},
+			bson.M{"effectiveUntil": bson.M{"$gt": date}},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "jurisdiction", Value: 1}, {Key: "name", Value: 1}}).SetCollation(caseInsensitive)
+	var rates []domain.TaxRate
+	err := repo.Policy.Run(ctx, "FindTaxRates", func(ctx context.Context) error {
+		rates = make([]domain.TaxRate, 0)
+		cur, err := collection.Find(ctx, filter, opts)
+		if err != nil {
+			return err
+		}
+		return cur.All(ctx, &rates)
+	})
+	if isServiceUnavailable(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("An error occurred while trying to find the tax rates of the category %s in %s. Message: %s", category, jurisdiction, err.Error()))
+	}
+	return rates, nil
+}
+
+func buildTaxRateRepository() appcontext.Component {
+	dbClient := appcontext.Current.Get(appcontext.DBClient).(*MongoClient)
+	breaker := appcontext.Current.Get(appcontext.DBCircuitBreaker).(*CircuitBreaker)
+	return &TaxRateRepository{Conn: dbClient.Conn, Policy: NewPolicy(breaker)}
+}
+
+func init() {
+	if config.Values.TaxRatesSource != config.TaxRatesMongoDB {
+		return
+	}
+	appcontext.Current.AddForProfiles(profiles, appcontext.TaxRateRepository, buildTaxRateRepository, appcontext.DBClient, appcontext.DBCircuitBreaker)
+}
@@ -0,0 +1,131 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//auditEntryColumns are selected in the order expected by scanAuditEntry
+const auditEntryColumns = "id, entity, entity_id, action, actor, actor_verified, request_id, occurred_at, changes"
+
+//AuditRepository reads the AuditEntry recorded by the ProjectRepository
+type AuditRepository struct {
+	Conn *sql.DB
+}
+
+//NewAuditRepository creates the AuditRepository over the connection pool
+func NewAuditRepository(conn *sql.DB) *AuditRepository {
+	return &AuditRepository{Conn: conn}
+}
+
+//audit records the entry in the transaction of the change, holding the changes as a JSON array
+func audit(ctx context.Context, tx *sql.Tx, entry domain.AuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO audit_entry (`+auditEntryColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		entry.ID.Hex(), entry.Entity, entry.EntityID, entry.Action, entry.Actor, entry.ActorVerified, nullString(entry.RequestID), entry.OccurredAt, string(changes))
+	return err
+}
+
+//scanAuditEntry maps a row of the audit_entry table
+func scanAuditEntry(row rowScanner) (*domain.AuditEntry, error) {
+	var id, changes string
+	var requestID sql.NullString
+	entry := domain.AuditEntry{}
+	if err := row.Scan(&id, &entry.Entity, &entry.EntityID, &entry.Action, &entry.Actor, &entry.ActorVerified, &requestID, &entry.OccurredAt, &changes); err != nil {
+		return nil, err
+	}
+	entryID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid audit entry ID stored in the database: %s. Message: %w", id, err)
+	}
+	entry.ID = entryID
+	entry.RequestID = requestID.String
+	entry.OccurredAt = entry.OccurredAt.UTC()
+	if err := json.Unmarshal([]byte(changes), &entry.Changes); err != nil {
+		return nil, fmt.Errorf("Invalid changes stored in the database: %s. Message: %w", changes, err)
+	}
+	return &entry, nil
+}
+
+//Find the entries matching the query ordered by ID, starting after the LastEntryID
+func (repo *AuditRepository) Find(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	if strings.TrimSpace(query.LastEntryID) != "" {
+		if _, err := primitive.ObjectIDFromHex(query.LastEntryID); err != nil {
+			return nil, domain.FieldViolation("lastEntryId", domain.ViolationFormat, "lastEntryId", query.LastEntryID)
+		}
+	}
+	conditions := []string{`id > $1`}
+	args := []interface{}{strings.TrimSpace(query.LastEntryID)}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if query.Entity != "" {
+		where(`entity = $%d`, query.Entity)
+	}
+	if query.EntityID != "" {
+		where(`entity_id = $%d`, query.EntityID)
+	}
+	if query.Actor != "" {
+		where(`actor = $%d`, query.Actor)
+	}
+	if !query.From.IsZero() {
+		where(`occurred_at >= $%d`, query.From.UTC())
+	}
+	if !query.Until.IsZero() {
+		where(`occurred_at < $%d`, query.Until.UTC())
+	}
+	//As in MongoDB, a limit of zero means no limit and a negative limit is the same as its absolute value
+	pageSize := query.PageSize
+	if pageSize < 0 {
+		pageSize = -pageSize
+	}
+	statement := `SELECT ` + auditEntryColumns + ` FROM audit_entry WHERE ` + strings.Join(conditions, ` AND `) + ` ORDER BY id`
+	if pageSize > 0 {
+		args = append(args, pageSize)
+		statement += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+
+	rows, err := repo.Conn.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("An error occurred while trying to find the audit entries. Message: %s", err.Error()))
+	}
+	defer func() { _ = rows.Close() }()
+	entries := make([]*domain.AuditEntry, 0)
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, domain.InternalError(fmt.Sprintf("An error occured while trying to convert the audit entry from the database. Message: %s", err.Error()))
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("An error occured while trying to convert the audit entries from the database. Message: %s", err.Error()))
+	}
+	return entries, nil
+}
+
+func buildAuditRepository() appcontext.Component {
+	dbClient := appcontext.Current.Get(appcontext.DBClient).(*SQLClient)
+	return NewAuditRepository(dbClient.Conn)
+}
+
+func init() {
+	if _, ok := drivers[config.Values.Repository]; !ok {
+		return
+	}
+	appcontext.Current.AddForProfiles(profiles, appcontext.AuditRepository, buildAuditRepository, appcontext.DBClient)
+}
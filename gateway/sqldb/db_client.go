@@ -0,0 +1,97 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+
+	//Drivers of the supported databases
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+//defaultSQLiteConnectionString is the database file used when no SQLConnectionString is configured for SQLite
+const defaultSQLiteConnectionString = "project.db"
+
+//drivers maps the Repository configuration to the database/sql driver name
+var drivers = map[string]string{
+	config.RepositoryPostgres: "postgres",
+	config.RepositorySQLite:   "sqlite",
+}
+
+//SQLClient keeps the pool of connections to the relational database
+type SQLClient struct {
+	Driver string
+	Conn   *sql.DB
+}
+
+//withDBTimeout derives the context for a DB operation from the request context, bounded by the configured DBTimeout
+func withDBTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, config.Values.DBTimeout)
+}
+
+//now returns the current time in UTC, with the millisecond precision used by every Repository
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+//Open the connection pool of the driver, checking it reaches the database and applying the pending migrations
+func Open(driver string, connectionString string) (*SQLClient, error) {
+	db, err := sql.Open(driver, connectionString)
+	if err != nil {
+		return nil, fmt.Errorf("An error occurred while trying to open a DB connection. Error message: %w", err)
+	}
+	if driver == drivers[config.RepositorySQLite] {
+		//SQLite allows a single writer, so the pool is serialized instead of failing with "database is locked"
+		db.SetMaxOpenConns(1)
+	}
+
+	ctx, cancel := withDBTimeout(context.Background())
+	defer cancel()
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("Failed to ping the database: %w", err)
+	}
+	if err = migrate(ctx, db, driver); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &SQLClient{Driver: driver, Conn: db}, nil
+}
+
+//Start is a no-op, as the connection is opened while the SQLClient is built
+func (client *SQLClient) Start(ctx context.Context) error {
+	return nil
+}
+
+//Stop closes the connections of the pool
+func (client *SQLClient) Stop(ctx context.Context) error {
+	return client.Conn.Close()
+}
+
+//CheckHealth pings the database, reporting whether it can serve requests
+func (client *SQLClient) CheckHealth(ctx context.Context) error {
+	return client.Conn.PingContext(ctx)
+}
+
+func buildSQLClient() (appcontext.Component, error) {
+	connectionString := config.Values.SQLConnectionString
+	if connectionString == "" && config.Values.Repository == config.RepositorySQLite {
+		connectionString = defaultSQLiteConnectionString
+	}
+	return Open(drivers[config.Values.Repository], connectionString)
+}
+
+//profiles in which the SQL gateway components are registered
+var profiles = []string{appcontext.ProfileProduction, appcontext.ProfileLocal}
+
+func init() {
+	if _, ok := drivers[config.Values.Repository]; !ok {
+		return
+	}
+	appcontext.Current.AddFactoryForProfiles(profiles, appcontext.DBClient, buildSQLClient)
+}
@@ -0,0 +1,157 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//migrationFiles are the schema changes, named <version>_<description>.sql and applied in the version order
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+//migration is a versioned schema change
+type migration struct {
+	version   int
+	name      string
+	statement string
+}
+
+//loadMigrations reads the embedded migrations sorted by version
+func loadMigrations() ([]migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("Could not list the migrations: %w", err)
+	}
+	migrations := make([]migration, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		version, err := strconv.Atoi(strings.SplitN(name, "_", 2)[0])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid migration file name %s. It must start with the version: %w", name, err)
+		}
+		statement, err := migrationFiles.ReadFile(path.Join("migrations", name))
+		if err != nil {
+			return nil, fmt.Errorf("Could not read the migration %s: %w", name, err)
+		}
+		migrations = append(migrations, migration{version: version, name: name, statement: string(statement)})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+	return migrations, nil
+}
+
+//migrationFunctions complete the migration of the same version, in its transaction, with the changes written in Go
+var migrationFunctions = map[int]func(ctx context.Context, tx *sql.Tx) error{
+	15: backfillProjectNameKeys,
+}
+
+//backfillProjectNameKeys stores the domain.FoldName of the existent names, failing with the names which would break the unique index of the migration 16
+func backfillProjectNameKeys(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `SELECT id, name FROM project ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	names := make(map[string]string)
+	keys := make(map[string][]string)
+	order := make([]string, 0)
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		key := domain.FoldName(name)
+		if _, ok := keys[key]; !ok {
+			order = append(order, key)
+		}
+		names[id] = key
+		keys[key] = append(keys[key], name)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	duplicates := make([]string, 0)
+	for _, key := range order {
+		if len(keys[key]) > 1 {
+			duplicates = append(duplicates, strings.Join(keys[key], " / "))
+		}
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("The Project names must be unique ignoring the case. Rename the Projects named %s", strings.Join(duplicates, ", "))
+	}
+	for id, key := range names {
+		if _, err := tx.ExecContext(ctx, `UPDATE project SET name_key = $1 WHERE id = $2`, key, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//migrationLockID identifies the PostgreSQL advisory lock which serializes the migrations run by concurrent instances
+const migrationLockID = 7_035_001
+
+//migrate applies the migrations not recorded in the schema_migrations table, each one in its own transaction
+func migrate(ctx context.Context, db *sql.DB, driver string) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL)`); err != nil {
+		return fmt.Errorf("Could not create the schema_migrations table: %w", err)
+	}
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	for _, migration := range migrations {
+		if err := apply(ctx, db, driver, migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//apply runs the migration and records its version in the same transaction, skipping it if it was already applied
+func apply(ctx context.Context, db *sql.DB, driver string, migration migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Could not start the transaction of the migration %s: %w", migration.name, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	//SQLite serializes the writers by itself, while PostgreSQL needs a lock held until the transaction ends
+	if driver == drivers[config.RepositoryPostgres] {
+		if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+			return fmt.Errorf("Could not lock the migration %s: %w", migration.name, err)
+		}
+	}
+
+	var applied int
+	if err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations WHERE version = $1`, migration.version).Scan(&applied); err != nil {
+		return fmt.Errorf("Could not check whether the migration %s was applied: %w", migration.name, err)
+	}
+	if applied > 0 {
+		return nil
+	}
+	if _, err = tx.ExecContext(ctx, migration.statement); err != nil {
+		return fmt.Errorf("Could not apply the migration %s: %w", migration.name, err)
+	}
+	if migrationFunction, ok := migrationFunctions[migration.version]; ok {
+		if err = migrationFunction(ctx, tx); err != nil {
+			return fmt.Errorf("Could not apply the migration %s: %w", migration.name, err)
+		}
+	}
+	if _, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`, migration.version, migration.name, now()); err != nil {
+		return fmt.Errorf("Could not record the migration %s: %w", migration.name, err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,9 @@
+CREATE TABLE project (
+    id VARCHAR(24) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    unit_price_amount BIGINT NOT NULL,
+    unit_price_currency VARCHAR(3) NOT NULL,
+    time_unit VARCHAR(16) NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    date_updated TIMESTAMP NULL
+);
@@ -0,0 +1 @@
+CREATE UNIQUE INDEX project_name_unique ON project (LOWER(name));
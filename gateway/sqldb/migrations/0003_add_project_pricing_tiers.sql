@@ -0,0 +1 @@
+ALTER TABLE project ADD COLUMN pricing_tiers TEXT NULL;
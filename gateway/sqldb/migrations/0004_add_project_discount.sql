@@ -0,0 +1 @@
+ALTER TABLE project ADD COLUMN discount VARCHAR(32) NULL;
@@ -0,0 +1 @@
+ALTER TABLE project ADD COLUMN deleted_at TIMESTAMP NULL;
@@ -0,0 +1 @@
+ALTER TABLE project ADD COLUMN deleted_by VARCHAR(255) NULL;
@@ -0,0 +1 @@
+CREATE INDEX project_deleted_at ON project (deleted_at);
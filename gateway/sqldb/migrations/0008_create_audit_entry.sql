@@ -0,0 +1,10 @@
+CREATE TABLE audit_entry (
+    id VARCHAR(24) PRIMARY KEY,
+    entity VARCHAR(64) NOT NULL,
+    entity_id VARCHAR(24) NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(128) NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    changes TEXT NOT NULL
+);
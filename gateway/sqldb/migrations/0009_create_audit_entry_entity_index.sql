@@ -0,0 +1 @@
+CREATE INDEX audit_entry_entity ON audit_entry (entity, entity_id, id);
@@ -0,0 +1 @@
+CREATE INDEX audit_entry_actor ON audit_entry (actor, occurred_at);
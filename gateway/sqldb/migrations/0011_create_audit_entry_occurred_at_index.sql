@@ -0,0 +1 @@
+CREATE INDEX audit_entry_occurred_at ON audit_entry (occurred_at);
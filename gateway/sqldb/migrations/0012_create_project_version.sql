@@ -0,0 +1,17 @@
+CREATE TABLE project_version (
+    id VARCHAR(24) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    unit_price_amount BIGINT NOT NULL,
+    unit_price_currency VARCHAR(3) NOT NULL,
+    time_unit VARCHAR(16) NOT NULL,
+    pricing_tiers TEXT NULL,
+    discount VARCHAR(32) NULL,
+    date_created TIMESTAMP NOT NULL,
+    date_updated TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    deleted_by VARCHAR(255) NULL,
+    version BIGINT NOT NULL,
+    version_date_created TIMESTAMP NOT NULL,
+    version_created_by VARCHAR(255) NOT NULL,
+    PRIMARY KEY (id, version)
+);
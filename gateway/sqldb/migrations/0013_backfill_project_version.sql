@@ -0,0 +1,2 @@
+INSERT INTO project_version (id, name, unit_price_amount, unit_price_currency, time_unit, pricing_tiers, discount, date_created, date_updated, deleted_at, deleted_by, version, version_date_created, version_created_by)
+SELECT id, name, unit_price_amount, unit_price_currency, time_unit, pricing_tiers, discount, date_created, date_updated, deleted_at, deleted_by, 1, COALESCE(deleted_at, date_updated, date_created), 'anonymous' FROM project;
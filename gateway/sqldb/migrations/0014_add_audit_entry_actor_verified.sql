@@ -0,0 +1 @@
+ALTER TABLE audit_entry ADD COLUMN actor_verified BOOLEAN NOT NULL DEFAULT FALSE;
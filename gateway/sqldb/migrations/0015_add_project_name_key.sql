@@ -0,0 +1 @@
+ALTER TABLE project ADD COLUMN name_key VARCHAR(255) NULL;
@@ -0,0 +1,2 @@
+DROP INDEX project_name_unique;
+CREATE UNIQUE INDEX project_name_unique ON project (name_key) WHERE deleted_at IS NULL;
@@ -0,0 +1,408 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+//projectNameIndex is the unique index of the name_key of the Projects not deleted. There is no tenant, so the names are unique in the whole table
+const projectNameIndex = "project_name_unique"
+
+//postgresUniqueViolation is the SQLSTATE of the unique index violations in PostgreSQL
+const postgresUniqueViolation = "23505"
+
+//projectColumns are selected in the order expected by scanProject
+const projectColumns = "id, name, unit_price_amount, unit_price_currency, time_unit, pricing_tiers, discount, date_created, date_updated, deleted_at, deleted_by"
+
+//ProjectRepository stores the Projects in a relational database. The queries use the same SQL in PostgreSQL and SQLite.
+//Each change is recorded in the audit_entry table in the same transaction
+type ProjectRepository struct {
+	Conn *sql.DB
+	//Driver is the database/sql driver name of the connection pool, selecting the row locks
+	Driver string
+}
+
+//NewProjectRepository creates the ProjectRepository over the connection pool of the driver
+func NewProjectRepository(conn *sql.DB, driver string) *ProjectRepository {
+	return &ProjectRepository{Conn: conn, Driver: driver}
+}
+
+//rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+//scanProject maps a row of the project table, holding the Money as an integer amount plus the currency code and the pricing tiers as a JSON array.
+//The columns selected after the projectColumns are scanned into the extra destinations
+func scanProject(row rowScanner, extra ...interface{}) (*domain.Project, error) {
+	var id string
+	var pricingTiers, discount sql.NullString
+	var dateUpdated, deletedAt sql.NullTime
+	var deletedBy sql.NullString
+	project := domain.Project{}
+	dest := []interface{}{&id, &project.Name, &project.UnitPrice.Amount, &project.UnitPrice.Currency, &project.TimeUnit, &pricingTiers, &discount, &project.DateCreated, &dateUpdated, &deletedAt, &deletedBy}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if pricingTiers.Valid {
+		if err := json.Unmarshal([]byte(pricingTiers.String), &project.PricingTiers); err != nil {
+			return nil, fmt.Errorf("Invalid pricing tiers stored in the database: %s. Message: %w", pricingTiers.String, err)
+		}
+	}
+	project.Discount = discount.String
+	projectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Project ID stored in the database: %s. Message: %w", id, err)
+	}
+	project.ID = projectID
+	project.DateCreated = project.DateCreated.UTC()
+	if dateUpdated.Valid {
+		project.DateUpdated = dateUpdated.Time.UTC()
+	}
+	if deletedAt.Valid {
+		project.DeletedAt = deletedAt.Time.UTC()
+	}
+	project.DeletedBy = deletedBy.String
+	return &project, nil
+}
+
+//nullPricingTiers stores the pricing tiers as a JSON array, or NULL if there is none
+func nullPricingTiers(tiers []domain.PricingTier) (sql.NullString, error) {
+	if len(tiers) == 0 {
+		return sql.NullString{}, nil
+	}
+	content, err := json.Marshal(tiers)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(content), Valid: true}, nil
+}
+
+//projectValues are the values of the projectColumns of the Project
+func projectValues(project *domain.Project) ([]interface{}, error) {
+	pricingTiers, err := nullPricingTiers(project.PricingTiers)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{project.ID.Hex(), project.Name, project.UnitPrice.Amount, project.UnitPrice.Currency, project.TimeUnit, pricingTiers, nullString(project.Discount), project.DateCreated, nullTime(project.DateUpdated),
+		nullTime(project.DeletedAt), nullString(project.DeletedBy)}, nil
+}
+
+//nullString stores the empty string as NULL
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+//nullTime stores the zero time as NULL
+func nullTime(value time.Time) sql.NullTime {
+	return sql.NullTime{Time: value, Valid: !value.IsZero()}
+}
+
+//Get a Project by ID
+func (repo *ProjectRepository) Get(ctx context.Context, id string) (*domain.Project, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", id)
+	}
+	project, err := scanProject(repo.Conn.QueryRowContext(ctx, `SELECT `+projectColumns+` FROM project WHERE id = $1 AND deleted_at IS NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NotFound(fmt.Sprintf("Could not find Project with the ID: %s", id))
+	}
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("Database fetch error while Getting the Project for ID: %s - Message: %s", id, err.Error()))
+	}
+	return project, nil
+}
+
+//Save a new project in the table, recording the AuditEntry in the same transaction
+func (repo *ProjectRepository) Save(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	if primitive.NilObjectID != project.ID {
+		return nil, domain.InternalError("The Save method should not be used for updating. Please use Update instead")
+	}
+	project.ID = primitive.NewObjectID()
+	project.DateCreated = now()
+	project.Undelete()
+	values, err := projectValues(project)
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("Could not convert the pricing tiers of the project. project: %+v - Message: %s", project, err.Error()))
+	}
+
+	err = repo.inTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO project (`+projectColumns+`, name_key) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`, append(values, domain.FoldName(project.Name))...); err != nil {
+			return err
+		}
+		return track(ctx, tx, domain.AuditCreated, nil, project, project.DateCreated)
+	})
+	if isDuplicateName(err) {
+		return nil, projectNameAlreadyExists(project)
+	}
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("Could not create the project. project: %+v - Message: %s", project, err.Error()))
+	}
+	return project, nil
+}
+
+//Update a project in the table, keeping its creation date and recording the AuditEntry in the same transaction
+func (repo *ProjectRepository) Update(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	pricingTiers, err := nullPricingTiers(project.PricingTiers)
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("Could not convert the pricing tiers of the project with ID = %s - Message: %s", project.ID.Hex(), err.Error()))
+	}
+
+	err = repo.inTransaction(ctx, func(tx *sql.Tx) error {
+		existentProject, err := repo.getForUpdate(ctx, tx, project.ID.Hex(), `deleted_at IS NULL`)
+		if err != nil {
+			return err
+		}
+		project.DateCreated = existentProject.DateCreated
+		project.DateUpdated = now()
+		project.Undelete()
+		_, err = tx.ExecContext(ctx, `UPDATE project SET name = $1, unit_price_amount = $2, unit_price_currency = $3, time_unit = $4, pricing_tiers = $5, discount = $6, date_updated = $7, name_key = $8 WHERE id = $9`,
+			project.Name, project.UnitPrice.Amount, project.UnitPrice.Currency, project.TimeUnit, pricingTiers, nullString(project.Discount), project.DateUpdated, domain.FoldName(project.Name), project.ID.Hex())
+		if err != nil {
+			return err
+		}
+		return track(ctx, tx, domain.AuditUpdated, existentProject, project, project.DateUpdated)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NotFound(fmt.Sprintf("Could not find Project with the ID: %s", project.ID.Hex()))
+	}
+	if isDuplicateName(err) {
+		return nil, projectNameAlreadyExists(project)
+	}
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("Could not update the project with ID = %s - Message: %s", project.ID.Hex(), err.Error()))
+	}
+	return project, nil
+}
+
+//GetAll Project ordered by ID, starting after the lastProjectID
+func (repo *ProjectRepository) GetAll(ctx context.Context, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	return repo.list(ctx, `deleted_at IS NULL`, lastProjectID, pageSize)
+}
+
+//GetTrash lists the deleted Projects ordered by ID, starting after the lastProjectID
+func (repo *ProjectRepository) GetTrash(ctx context.Context, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	return repo.list(ctx, `deleted_at IS NOT NULL`, lastProjectID, pageSize)
+}
+
+//list the Projects matching the deletion condition ordered by ID, starting after the lastProjectID
+func (repo *ProjectRepository) list(ctx context.Context, deletionCondition string, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	projectList := make([]*domain.Project, 0)
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	if strings.TrimSpace(lastProjectID) != "" {
+		if _, err := primitive.ObjectIDFromHex(lastProjectID); err != nil {
+			return nil, domain.FieldViolation("lastProjectId", domain.ViolationFormat, "lastProjectId", lastProjectID)
+		}
+	}
+	//As in MongoDB, a limit of zero means no limit and a negative limit is the same as its absolute value
+	if pageSize < 0 {
+		pageSize = -pageSize
+	}
+	query := `SELECT ` + projectColumns + ` FROM project WHERE ` + deletionCondition + ` AND id > $1 ORDER BY id`
+	args := []interface{}{strings.TrimSpace(lastProjectID)}
+	if pageSize > 0 {
+		query += ` LIMIT $2`
+		args = append(args, pageSize)
+	}
+
+	rows, err := repo.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("An error occurred while trying to find the project List. Message: %s", err.Error()))
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, domain.InternalError(fmt.Sprintf("An error occured while trying to convert the Project from the database. Message: %s", err.Error()))
+		}
+		projectList = append(projectList, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("An error occured while trying to convert the list of Project from the database. Message: %s", err.Error()))
+	}
+	return projectList, nil
+}
+
+//Delete moves the Project with the ID to the trash, recording the AuditEntry in the same transaction
+func (repo *ProjectRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", id)
+	}
+	err := repo.inTransaction(ctx, func(tx *sql.Tx) error {
+		before, err := repo.getForUpdate(ctx, tx, id, `deleted_at IS NULL`)
+		if err != nil {
+			return err
+		}
+		after := *before
+		after.DeletedAt = now()
+		after.DeletedBy = deletedBy
+		if _, err := tx.ExecContext(ctx, `UPDATE project SET deleted_at = $1, deleted_by = $2 WHERE id = $3`, after.DeletedAt, after.DeletedBy, id); err != nil {
+			return err
+		}
+		return track(ctx, tx, domain.AuditDeleted, before, &after, after.DeletedAt)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NotFound(fmt.Sprintf("Could not find Project with the ID: %s", id))
+	}
+	if err != nil {
+		return domain.InternalError(fmt.Sprintf("Database error while deleting the Project with ID: %s - Message: %s", id, err.Error()))
+	}
+	return nil
+}
+
+//Restore takes the deleted Project with the ID back from the trash, recording the AuditEntry in the same transaction
+func (repo *ProjectRepository) Restore(ctx context.Context, id string) (*domain.Project, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", id)
+	}
+	var project domain.Project
+	err := repo.inTransaction(ctx, func(tx *sql.Tx) error {
+		before, err := repo.getForUpdate(ctx, tx, id, `deleted_at IS NOT NULL`)
+		if err != nil {
+			return err
+		}
+		project = *before
+		project.Undelete()
+		if _, err := tx.ExecContext(ctx, `UPDATE project SET deleted_at = NULL, deleted_by = NULL WHERE id = $1`, id); err != nil {
+			return err
+		}
+		return track(ctx, tx, domain.AuditRestored, before, &project, now())
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NotFound(fmt.Sprintf("Could not find the deleted Project with the ID: %s", id))
+	}
+	if isDuplicateName(err) {
+		return nil, projectNameAlreadyExists(&project)
+	}
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("Database error while restoring the Project with ID: %s - Message: %s", id, err.Error()))
+	}
+	return &project, nil
+}
+
+//Purge permanently removes the Projects deleted before the date, recording an AuditEntry for each one in the same transaction.
+//Their previous AuditEntry and ProjectVersion are kept
+func (repo *ProjectRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	purged := int64(0)
+	err := repo.inTransaction(ctx, func(tx *sql.Tx) error {
+		projects, err := deleteProjects(ctx, tx, `deleted_at < $1`, deletedBefore.UTC())
+		if err != nil {
+			return err
+		}
+		for _, project := range projects {
+			if err := audit(ctx, tx, domain.NewProjectAuditEntry(ctx, domain.AuditPurged, project, nil)); err != nil {
+				return err
+			}
+		}
+		purged = int64(len(projects))
+		return nil
+	})
+	if err != nil {
+		return 0, domain.InternalError(fmt.Sprintf("Database error while purging the Projects deleted before %s - Message: %s", deletedBefore.Format(time.RFC3339), err.Error()))
+	}
+	return purged, nil
+}
+
+//deleteProjects deletes the Projects matching the condition in the transaction, returning them.
+//The rows are read before the next statement, as the transaction runs the statements in a single connection
+func deleteProjects(ctx context.Context, tx *sql.Tx, condition string, args ...interface{}) ([]*domain.Project, error) {
+	rows, err := tx.QueryContext(ctx, `DELETE FROM project WHERE `+condition+` RETURNING `+projectColumns, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	projects := make([]*domain.Project, 0)
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	return projects, rows.Err()
+}
+
+//inTransaction runs the writes in a transaction, committed only when all of them succeed
+func (repo *ProjectRepository) inTransaction(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := repo.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+//track records the change of the Project, made at the date, in the audit_entry and the project_version tables
+func track(ctx context.Context, tx *sql.Tx, action string, before *domain.Project, after *domain.Project, date time.Time) error {
+	if err := audit(ctx, tx, domain.NewProjectAuditEntry(ctx, action, before, after)); err != nil {
+		return err
+	}
+	return recordVersion(ctx, tx, after, date)
+}
+
+//getForUpdate reads the Project matching the deletion condition in the transaction, locking its row until the transaction ends.
+//SQLite has no row locks, as its single writer already serializes the transactions
+func (repo *ProjectRepository) getForUpdate(ctx context.Context, tx *sql.Tx, id string, deletionCondition string) (*domain.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM project WHERE id = $1 AND ` + deletionCondition
+	if repo.Driver == drivers[config.RepositoryPostgres] {
+		query += ` FOR UPDATE`
+	}
+	return scanProject(tx.QueryRowContext(ctx, query, id))
+}
+
+//isDuplicateName checks if the error was caused by the unique index of the name_key, the domain.FoldName of the project names, including
+//the restore of a deleted Project whose name was taken in the meantime
+func isDuplicateName(err error) bool {
+	var postgresError *pq.Error
+	if errors.As(err, &postgresError) {
+		return postgresError.Code == postgresUniqueViolation && postgresError.Constraint == projectNameIndex
+	}
+	//SQLite reports the violations of the primary key with another code
+	var sqliteError *sqlite.Error
+	return errors.As(err, &sqliteError) && sqliteError.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+func projectNameAlreadyExists(project *domain.Project) domain.AlreadyExistsError {
+	return domain.AlreadyExistsForField("name", fmt.Sprintf("A Project named %s already exists", project.Name))
+}
+
+func buildProjectRepository() appcontext.Component {
+	dbClient := appcontext.Current.Get(appcontext.DBClient).(*SQLClient)
+	return NewProjectRepository(dbClient.Conn, dbClient.Driver)
+}
+
+func init() {
+	if _, ok := drivers[config.Values.Repository]; !ok {
+		return
+	}
+	appcontext.Current.AddForProfiles(profiles, appcontext.ProjectRepository, buildProjectRepository, appcontext.DBClient)
+}
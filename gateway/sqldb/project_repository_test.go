@@ -0,0 +1,315 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func init() {
+	config.UseProfile(appcontext.ProfileTest)
+}
+
+//newSQLiteRepository creates a ProjectRepository over a migrated SQLite database in a temporary directory
+func newSQLiteRepository(t *testing.T) *ProjectRepository {
+	client, err := Open("sqlite", filepath.Join(t.TempDir(), "project.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Stop(context.Background()) })
+	return NewProjectRepository(client.Conn, client.Driver)
+}
+
+func newProject(name string) *domain.Project {
+	return &domain.Project{
+		Name:      name,
+		UnitPrice: domain.Money{Amount: 10000, Currency: "EUR"},
+		TimeUnit:  "Hour",
+	}
+}
+
+func errorCode(err error) int {
+	if identifiableError, ok := err.(domain.IdentifiableError); ok {
+		return identifiableError.GetCode()
+	}
+	return 0
+}
+
+func TestMigrate(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "project.db")
+	client, err := Open("sqlite", fileName)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_ = client.Stop(context.Background())
+
+	//Opening the migrated database again skips the applied migrations
+	client, err = Open("sqlite", fileName)
+	if assert.NoError(t, err) {
+		defer func() { _ = client.Stop(context.Background()) }()
+		var applied int
+		assert.NoError(t, client.Conn.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&applied))
+		migrations, _ := loadMigrations()
+		assert.Equal(t, len(migrations), applied)
+	}
+}
+
+func TestBackfillProjectNameKeys(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "project.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+	//The names differing only by the case of non-ASCII letters were accepted by the LOWER index replaced in the migration 16
+	_, err = db.Exec(`CREATE TABLE project (id VARCHAR(24) PRIMARY KEY, name VARCHAR(255) NOT NULL, name_key VARCHAR(255) NULL)`)
+	assert.NoError(t, err)
+	for id, name := range []string{"Ébène", "Other", "ébène"} {
+		_, err = db.Exec(`INSERT INTO project (id, name) VALUES ($1, $2)`, fmt.Sprint(id), name)
+		assert.NoError(t, err)
+	}
+
+	tx, _ := db.BeginTx(ctx, nil)
+	defer func() { _ = tx.Rollback() }()
+	err = backfillProjectNameKeys(ctx, tx)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Ébène / ébène")
+	}
+	_, err = tx.Exec(`DELETE FROM project WHERE id = '2'`)
+	assert.NoError(t, err)
+	assert.NoError(t, backfillProjectNameKeys(ctx, tx))
+	var key string
+	assert.NoError(t, tx.QueryRow(`SELECT name_key FROM project WHERE id = '0'`).Scan(&key))
+	assert.Equal(t, "ébène", key)
+}
+
+func TestSaveAndGet(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	saved, err := repo.Save(ctx, newProject("Project API"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEqual(t, primitive.NilObjectID, saved.ID)
+	_, err = repo.Save(ctx, saved)
+	assert.Equal(t, 500, errorCode(err))
+
+	tests := []struct {
+		name         string
+		projectID    string
+		expectedCode int
+	}{
+		{name: "Found", projectID: saved.ID.Hex()},
+		{name: "Not Found", projectID: primitive.NewObjectID().Hex(), expectedCode: 404},
+		{name: "Invalid ID", projectID: "invalid", expectedCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project, err := repo.Get(ctx, tt.projectID)
+			assert.Equal(t, tt.expectedCode, errorCode(err))
+			if tt.expectedCode == 0 {
+				assert.Equal(t, saved.ID, project.ID)
+				assert.Equal(t, saved.UnitPrice, project.UnitPrice)
+				assert.True(t, saved.DateCreated.Equal(project.DateCreated))
+				assert.True(t, project.DateUpdated.IsZero())
+				assert.Empty(t, project.PricingTiers)
+				assert.Empty(t, project.Discount)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+	saved, _ := repo.Save(ctx, newProject("Project API"))
+
+	changed := newProject("Renamed")
+	changed.ID = saved.ID
+	changed.UnitPrice = domain.Money{Amount: 12050, Currency: "USD"}
+	changed.PricingTiers = []domain.PricingTier{{From: "100", UnitPrice: domain.Money{Amount: 9000, Currency: "USD"}}}
+	changed.Discount = "7.5"
+	project, err := repo.Update(ctx, changed)
+	if assert.NoError(t, err) {
+		assert.True(t, saved.DateCreated.Equal(project.DateCreated))
+		assert.False(t, project.DateUpdated.IsZero())
+
+		stored, _ := repo.Get(ctx, saved.ID.Hex())
+		assert.Equal(t, "Renamed", stored.Name)
+		assert.Equal(t, domain.Money{Amount: 12050, Currency: "USD"}, stored.UnitPrice)
+		assert.Equal(t, changed.PricingTiers, stored.PricingTiers)
+		assert.Equal(t, "7.5", stored.Discount)
+		assert.True(t, project.DateUpdated.Equal(stored.DateUpdated))
+	}
+
+	missing := newProject("Missing")
+	missing.ID = primitive.NewObjectID()
+	_, err = repo.Update(ctx, missing)
+	assert.Equal(t, 404, errorCode(err))
+}
+
+func TestGetAll(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+	ids := make([]string, 0)
+	for i := 0; i < 5; i++ {
+		project, _ := repo.Save(ctx, newProject(fmt.Sprintf("Project %d", i)))
+		ids = append(ids, project.ID.Hex())
+	}
+
+	tests := []struct {
+		name          string
+		lastProjectID string
+		pageSize      int64
+		expectedIDs   []string
+		expectedCode  int
+	}{
+		{name: "First Page", pageSize: 2, expectedIDs: ids[:2]},
+		{name: "Next Page", lastProjectID: ids[1], pageSize: 2, expectedIDs: ids[2:4]},
+		{name: "Last Page", lastProjectID: ids[3], pageSize: 2, expectedIDs: ids[4:]},
+		{name: "No Limit", pageSize: 0, expectedIDs: ids},
+		{name: "Negative Limit", pageSize: -3, expectedIDs: ids[:3]},
+		{name: "Invalid ID", lastProjectID: "invalid", pageSize: 2, expectedCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projects, err := repo.GetAll(ctx, tt.lastProjectID, tt.pageSize)
+			assert.Equal(t, tt.expectedCode, errorCode(err))
+			if tt.expectedCode == 0 {
+				projectIDs := make([]string, 0)
+				for _, project := range projects {
+					projectIDs = append(projectIDs, project.ID.Hex())
+				}
+				assert.Equal(t, tt.expectedIDs, projectIDs)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+	saved, _ := repo.Save(ctx, newProject("Project API"))
+
+	assert.NoError(t, repo.Delete(ctx, saved.ID.Hex(), "alice"))
+	assert.Equal(t, 404, errorCode(repo.Delete(ctx, saved.ID.Hex(), "alice")))
+	assert.Equal(t, 400, errorCode(repo.Delete(ctx, "invalid", "alice")))
+
+	//The deleted Project is kept in the trash, out of the other reads
+	_, err := repo.Get(ctx, saved.ID.Hex())
+	assert.Equal(t, 404, errorCode(err))
+	_, err = repo.Update(ctx, saved)
+	assert.Equal(t, 404, errorCode(err))
+	projects, _ := repo.GetAll(ctx, "", 0)
+	assert.Empty(t, projects)
+	trash, err := repo.GetTrash(ctx, "", 0)
+	if assert.NoError(t, err) && assert.Len(t, trash, 1) {
+		assert.Equal(t, saved.ID, trash[0].ID)
+		assert.Equal(t, "alice", trash[0].DeletedBy)
+		assert.True(t, trash[0].Deleted())
+	}
+	//The deleted Project releases its name
+	_, err = repo.Save(ctx, newProject("project api"))
+	assert.NoError(t, err)
+}
+
+func TestRestore(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+	saved, _ := repo.Save(ctx, newProject("Project API"))
+
+	_, err := repo.Restore(ctx, saved.ID.Hex())
+	assert.Equal(t, 404, errorCode(err))
+	_, err = repo.Restore(ctx, "invalid")
+	assert.Equal(t, 400, errorCode(err))
+
+	assert.NoError(t, repo.Delete(ctx, saved.ID.Hex(), "alice"))
+	restored, err := repo.Restore(ctx, saved.ID.Hex())
+	if assert.NoError(t, err) {
+		assert.Equal(t, saved.ID, restored.ID)
+		assert.False(t, restored.Deleted())
+		assert.Empty(t, restored.DeletedBy)
+	}
+	_, err = repo.Get(ctx, saved.ID.Hex())
+	assert.NoError(t, err)
+	trash, _ := repo.GetTrash(ctx, "", 0)
+	assert.Empty(t, trash)
+
+	//The Project is not restored while another one has its name
+	assert.NoError(t, repo.Delete(ctx, saved.ID.Hex(), "alice"))
+	_, err = repo.Save(ctx, newProject("PROJECT API"))
+	assert.NoError(t, err)
+	_, err = repo.Restore(ctx, saved.ID.Hex())
+	if alreadyExists, ok := err.(domain.AlreadyExistsError); assert.True(t, ok) {
+		assert.Equal(t, "name", alreadyExists.Field)
+	}
+	trash, _ = repo.GetTrash(ctx, "", 0)
+	assert.Len(t, trash, 1)
+}
+
+func TestPurge(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+	kept, _ := repo.Save(ctx, newProject("Kept"))
+	old, _ := repo.Save(ctx, newProject("Old"))
+	recent, _ := repo.Save(ctx, newProject("Recent"))
+	assert.NoError(t, repo.Delete(ctx, old.ID.Hex(), "alice"))
+	cutoff := now().Add(time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+	assert.NoError(t, repo.Delete(ctx, recent.ID.Hex(), "alice"))
+
+	purged, err := repo.Purge(ctx, cutoff)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(1), purged)
+	}
+	_, err = repo.Get(ctx, kept.ID.Hex())
+	assert.NoError(t, err)
+	trash, _ := repo.GetTrash(ctx, "", 0)
+	if assert.Len(t, trash, 1) {
+		assert.Equal(t, recent.ID, trash[0].ID)
+	}
+	//The purged Project releases its name
+	_, err = repo.Save(ctx, newProject("Old"))
+	assert.NoError(t, err)
+}
+
+func TestUniqueName(t *testing.T) {
+	repo := newSQLiteRepository(t)
+	ctx := context.Background()
+	saved, _ := repo.Save(ctx, newProject("Project API"))
+	other, _ := repo.Save(ctx, newProject("Project UI"))
+
+	_, err := repo.Save(ctx, newProject("project api"))
+	if alreadyExists, ok := err.(domain.AlreadyExistsError); assert.True(t, ok) {
+		assert.Equal(t, 409, alreadyExists.Code)
+		assert.Equal(t, "name", alreadyExists.Field)
+	}
+
+	other.Name = "PROJECT API"
+	_, err = repo.Update(ctx, other)
+	assert.Equal(t, 409, errorCode(err))
+
+	//A Project keeps its own name, even changing the case
+	saved.Name = "Project Api"
+	_, err = repo.Update(ctx, saved)
+	assert.NoError(t, err)
+
+	//The case is ignored beyond the ASCII letters, as in the other Repositories
+	_, err = repo.Save(ctx, newProject("Ébène"))
+	assert.NoError(t, err)
+	_, err = repo.Save(ctx, newProject("ébène"))
+	assert.Equal(t, 409, errorCode(err))
+	other.Name = "ÉBÈNE"
+	_, err = repo.Update(ctx, other)
+	assert.Equal(t, 409, errorCode(err))
+}
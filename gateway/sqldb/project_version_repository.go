@@ -0,0 +1,95 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//projectVersionColumns are the projectColumns of the snapshot followed by the columns of the version, in the order expected by scanProjectVersion
+const projectVersionColumns = projectColumns + ", version, version_date_created, version_created_by"
+
+//ProjectVersionRepository reads the ProjectVersion recorded by the ProjectRepository
+type ProjectVersionRepository struct {
+	Conn *sql.DB
+}
+
+//NewProjectVersionRepository creates the ProjectVersionRepository over the connection pool
+func NewProjectVersionRepository(conn *sql.DB) *ProjectVersionRepository {
+	return &ProjectVersionRepository{Conn: conn}
+}
+
+//recordVersion inserts the next version of the Project, changed at the date, in the transaction of the change.
+//The row of the Project is locked by the transaction, so the concurrent changes cannot take the same number
+func recordVersion(ctx context.Context, tx *sql.Tx, project *domain.Project, date time.Time) error {
+	var number int64
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) + 1 FROM project_version WHERE id = $1`, project.ID.Hex()).Scan(&number); err != nil {
+		return err
+	}
+	version := domain.NewProjectVersion(ctx, number, project, date)
+	values, err := projectValues(&version.Project)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO project_version (`+projectVersionColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		append(values, version.Number, version.DateCreated, version.CreatedBy)...)
+	return err
+}
+
+//scanProjectVersion maps a row of the project_version table
+func scanProjectVersion(row rowScanner) (*domain.ProjectVersion, error) {
+	version := domain.ProjectVersion{}
+	project, err := scanProject(row, &version.Number, &version.DateCreated, &version.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	version.Project = *project
+	version.DateCreated = version.DateCreated.UTC()
+	return &version, nil
+}
+
+//Get the version number of the Project
+func (repo *ProjectVersionRepository) Get(ctx context.Context, projectID string, number int64) (*domain.ProjectVersion, error) {
+	return repo.find(ctx, projectID, fmt.Sprintf("the version %d", number), `SELECT `+projectVersionColumns+` FROM project_version WHERE id = $1 AND version = $2`, projectID, number)
+}
+
+//GetAsOf gets the version of the Project current at the date, the last one created until then
+func (repo *ProjectVersionRepository) GetAsOf(ctx context.Context, projectID string, asOf time.Time) (*domain.ProjectVersion, error) {
+	return repo.find(ctx, projectID, "a version as of "+asOf.Format(time.RFC3339), `SELECT `+projectVersionColumns+` FROM project_version WHERE id = $1 AND version_date_created <= $2 ORDER BY version DESC LIMIT 1`, projectID, asOf.UTC())
+}
+
+//find the version of the Project selected by the query, described for the errors
+func (repo *ProjectVersionRepository) find(ctx context.Context, projectID string, description string, query string, args ...interface{}) (*domain.ProjectVersion, error) {
+	ctx, cancel := withDBTimeout(ctx)
+	defer cancel()
+	if _, err := primitive.ObjectIDFromHex(projectID); err != nil {
+		return nil, domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", projectID)
+	}
+	version, err := scanProjectVersion(repo.Conn.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NotFound(fmt.Sprintf("Could not find %s of the Project with the ID: %s", description, projectID))
+	}
+	if err != nil {
+		return nil, domain.InternalError(fmt.Sprintf("Database fetch error while Getting %s of the Project with ID: %s - Message: %s", description, projectID, err.Error()))
+	}
+	return version, nil
+}
+
+func buildProjectVersionRepository() appcontext.Component {
+	dbClient := appcontext.Current.Get(appcontext.DBClient).(*SQLClient)
+	return NewProjectVersionRepository(dbClient.Conn)
+}
+
+func init() {
+	if _, ok := drivers[config.Values.Repository]; !ok {
+		return
+	}
+	appcontext.Current.AddForProfiles(profiles, appcontext.ProjectVersionRepository, buildProjectVersionRepository, appcontext.DBClient)
+}
@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	//ExporterOTLP exports the spans using OTLP over HTTP
+	ExporterOTLP = "otlp"
+	//ExporterStdout writes the spans to the standard output
+	ExporterStdout = "stdout"
+	//ExporterNone keeps the spans (and the Trace IDs in the logs) but does not export them
+	ExporterNone = "none"
+)
+
+//TracerProvider wraps the OpenTelemetry SDK TracerProvider registered as the global one
+type TracerProvider struct {
+	Provider *sdktrace.TracerProvider
+}
+
+//Start is a no-op, as the TracerProvider is registered while it is built
+func (tracerProvider *TracerProvider) Start(ctx context.Context) error {
+	return nil
+}
+
+//Stop flushes the pending spans and stops the exporter
+func (tracerProvider *TracerProvider) Stop(ctx context.Context) error {
+	return tracerProvider.Provider.Shutdown(ctx)
+}
+
+func buildSpanExporter(exporter string) (sdktrace.SpanExporter, error) {
+	switch exporter {
+	case ExporterOTLP:
+		return otlptracehttp.New(context.Background())
+	case ExporterStdout:
+		return stdouttrace.New()
+	case ExporterNone, "":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("Unknown tracing exporter: %s. Use any of [%s, %s, %s]", exporter, ExporterOTLP, ExporterStdout, ExporterNone)
+}
+
+//buildTracerProvider creates the TracerProvider and registers it, with the W3C Trace Context propagator, as the global one
+func buildTracerProvider() appcontext.Component {
+	logger := config.GetLogger()
+	defer logger.Sync()
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.Values.TracingSampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(config.Values.AppName),
+			semconv.ServiceVersion(config.Version),
+		)),
+	}
+	exporter, err := buildSpanExporter(config.Values.TracingExporter)
+	if err != nil {
+		logger.Errorf("Spans will not be exported. Message: %s", err.Error())
+	}
+	if exporter != nil {
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return &TracerProvider{Provider: provider}
+}
+
+func init() {
+	appcontext.Current.Add(appcontext.TracerProvider, buildTracerProvider, appcontext.Logger)
+}
@@ -1,17 +1,65 @@
 package main
 
 import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
 	"github.com/danilovalente/project-api/config"
-_ "github.com/danilovalente/project-api/usecase"
-_ "github.com/danilovalente/project-api/gateway/mongodb"
 	"github.com/danilovalente/project-api/controller"
+	_ "github.com/danilovalente/project-api/gateway/cache"
 	_ "github.com/danilovalente/project-api/gateway/customlog"
+	_ "github.com/danilovalente/project-api/gateway/memory"
+	_ "github.com/danilovalente/project-api/gateway/metrics"
+	_ "github.com/danilovalente/project-api/gateway/mongodb"
+	_ "github.com/danilovalente/project-api/gateway/sqldb"
+	_ "github.com/danilovalente/project-api/gateway/telemetry"
+	_ "github.com/danilovalente/project-api/usecase"
 	"github.com/labstack/echo/v4"
 )
 
 func main() {
+	logger := config.GetLogger()
+	if err := appcontext.Current.InitializeAll(); err != nil {
+		logger.Fatalf("Could not initialize the application components: %s", err.Error())
+	}
+	if err := appcontext.Current.StartAll(context.Background()); err != nil {
+		logger.Fatalf("Could not start the application components: %s", err.Error())
+	}
+
 	e := echo.New()
 	controller.MapRoutes(e)
 
-	e.Logger.Fatal(e.Start(":" + config.Values.Port))
+	go func() {
+		if err := e.Start(":" + config.Values.Port); err != nil && err != http.ErrServerClosed {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+	shutdown(e)
+}
+
+//shutdown fails the readiness probe, drains the in-flight requests and stops the components in the reverse dependency order
+func shutdown(e *echo.Echo) {
+	logger := config.GetLogger()
+	logger.Infof("Shutting down. Waiting %s for the readiness probe to report DOWN", config.Values.ShutdownDelay)
+	controller.Drain()
+	time.Sleep(config.Values.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Values.ShutdownGracePeriod)
+	defer cancel()
+	if err := e.Shutdown(ctx); err != nil {
+		logger.Errorf("Could not drain the in-flight requests: %s", err.Error())
+	}
+	logger.Info("Stopping the application components")
+	if err := appcontext.Current.StopAll(ctx); err != nil {
+		logger.Errorf("Could not stop the application components: %s", err.Error())
+	}
 }
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//AuditSearch represents the Usecase which searches the AuditEntry of every entity, for the administrators
+type AuditSearch struct {
+	auditRepository domain.AuditRepository
+}
+
+//Execute finds the entries matching the query, with paging
+func (u *AuditSearch) Execute(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	ctx, execution := startExecution(ctx, "AuditSearch")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+
+	entries, err := u.auditRepository.Find(ctx, query)
+	if err != nil {
+		msg := fmt.Sprintf("Could not search the audit entries. Query: %+v - Message: %s\n", query, err.Error())
+		execution.fail(err)
+		logger.Error(msg)
+		return nil, err
+	}
+	return entries, nil
+}
+
+func buildAuditSearchUsecase() appcontext.Component {
+	return &AuditSearch{
+		auditRepository: domain.GetAuditRepository(),
+	}
+}
+
+func init() {
+	appcontext.Current.Add(appcontext.AuditSearchUsecase, buildAuditSearchUsecase, appcontext.AuditRepository)
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//publish the ProjectEvent to the other instances. The change is already persisted, so a failure is only logged
+func publish(ctx context.Context, eventType string, projectID string) {
+	if err := domain.GetEventBus().Publish(ctx, domain.NewProjectEvent(ctx, eventType, projectID)); err != nil {
+		config.GetContextLogger(ctx).Errorf("Could not publish the %s event of the Project %s. Message: %s", eventType, projectID, err.Error())
+	}
+}
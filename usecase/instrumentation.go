@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/danilovalente/project-api/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//tracerName identifies the instrumentation scope of the Usecase spans
+const tracerName = "github.com/danilovalente/project-api/usecase"
+
+//execution instruments a Usecase execution with a span and the latency metrics
+type execution struct {
+	usecase string
+	start   time.Time
+	span    trace.Span
+	err     error
+}
+
+//startExecution starts the Usecase span as a child of the span carried by the context
+func startExecution(ctx context.Context, usecase string) (context.Context, *execution) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, usecase+".Execute")
+	return ctx, &execution{usecase: usecase, start: time.Now(), span: span}
+}
+
+//fail flags the execution as failed by the provided error
+func (e *execution) fail(err error) {
+	e.err = err
+	e.span.RecordError(err)
+	e.span.SetStatus(codes.Error, err.Error())
+}
+
+//end records the execution latency and ends the span
+func (e *execution) end() {
+	config.GetMetrics().ObserveUsecase(e.usecase, time.Since(e.start), e.err)
+	e.span.End()
+}
@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/danilovalente/project-api/appcontext"
 	"github.com/danilovalente/project-api/config"
 	"github.com/danilovalente/project-api/domain"
@@ -12,21 +14,27 @@ type ProjectCreate struct {
 }
 
 //Execute creates/persists the project
-func (u *ProjectCreate) Execute(project *domain.Project) (*domain.Project, error) {
-	logger := config.GetLogger
-	defer logger().Sync()
-	logger().Debugf("Project %+v \n", project)
+func (u *ProjectCreate) Execute(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	ctx, execution := startExecution(ctx, "ProjectCreate")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+	logger.Debugf("Project %+v \n", project)
 
 	valid, err := project.Valid()
 	if !valid {
-		logger().Error(err.Error())
+		execution.fail(err)
+		logger.Error(err.Error())
 		return nil, err
 	}
-	project, err = u.projectRepository.Save(project)
+	project, err = u.projectRepository.Save(ctx, project)
 	if err != nil {
-		logger().Errorf("Could not save project into repository. Error %s", err.Error())
+		execution.fail(err)
+		logger.Errorf("Could not save project into repository. Error %s", err.Error())
 		return nil, err
 	}
+	config.GetMetrics().ProjectChanged("created", project.UnitPrice.Currency, project.TimeUnit)
+	publish(ctx, domain.ProjectCreatedEvent, project.ID.Hex())
 	return project, nil
 }
 
@@ -37,8 +45,5 @@ func buildProjectCreateUsecase() appcontext.Component {
 }
 
 func init() {
-	if config.Values.TestRun {
-		return
-	}
-	appcontext.Current.Add(appcontext.ProjectCreateUsecase, buildProjectCreateUsecase)
+	appcontext.Current.Add(appcontext.ProjectCreateUsecase, buildProjectCreateUsecase, appcontext.ProjectRepository)
 }
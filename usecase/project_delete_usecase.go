@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/danilovalente/project-api/appcontext"
@@ -8,23 +9,31 @@ import (
 	"github.com/danilovalente/project-api/domain"
 )
 
-//ProjectDelete represents the Usecase which orchestrates the Project deletion from the database
+//ProjectDelete represents the Usecase which orchestrates the Project deletion, moving it to the trash
 type ProjectDelete struct {
 	projectRepository domain.ProjectRepository
 }
 
-//Execute deletes the Project with the provided ID
-func (u *ProjectDelete) Execute(ID string) error {
-	logger := config.GetLogger
-	defer logger().Sync()
+//Execute moves the Project with the provided ID to the trash, marked as deleted by the actor of the context
+func (u *ProjectDelete) Execute(ctx context.Context, ID string) error {
+	ctx, execution := startExecution(ctx, "ProjectDelete")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
 
 	projectRepository := u.projectRepository
-	err := projectRepository.Delete(ID)
+	project, err := projectRepository.Get(ctx, ID)
+	if err == nil {
+		err = projectRepository.Delete(ctx, ID, domain.Actor(ctx))
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Could not delete the Project with ID: %s. Message: %s\n", ID, err.Error())
-		logger().Error(msg)
+		execution.fail(err)
+		logger.Error(msg)
 		return err
 	}
+	config.GetMetrics().ProjectChanged("deleted", project.UnitPrice.Currency, project.TimeUnit)
+	publish(ctx, domain.ProjectDeletedEvent, ID)
 	return nil
 }
 
@@ -36,8 +45,5 @@ func buildProjectDeleteUsecase() appcontext.Component {
 }
 
 func init() {
-	if config.Values.TestRun {
-		return
-	}
-	appcontext.Current.Add(appcontext.ProjectDeleteUsecase, buildProjectDeleteUsecase)
+	appcontext.Current.Add(appcontext.ProjectDeleteUsecase, buildProjectDeleteUsecase, appcontext.ProjectRepository)
 }
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//ProjectDiff represents the Usecase which compares two versions of a Project
+type ProjectDiff struct {
+	projectVersionRepository domain.ProjectVersionRepository
+}
+
+//Execute lists the fields of the Project with the provided ID changed from the version from to the version to
+func (u *ProjectDiff) Execute(ctx context.Context, ID string, from int64, to int64) (*domain.ProjectDiff, error) {
+	ctx, execution := startExecution(ctx, "ProjectDiff")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+
+	versions := make([]*domain.ProjectVersion, 0, 2)
+	for _, number := range []int64{from, to} {
+		version, err := u.projectVersionRepository.Get(ctx, ID, number)
+		if err != nil {
+			msg := fmt.Sprintf("Could not compare the versions %d and %d of the Project with ID: %s. Message: %s\n", from, to, ID, err.Error())
+			execution.fail(err)
+			logger.Error(msg)
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return &domain.ProjectDiff{
+		ProjectID: ID,
+		From:      from,
+		To:        to,
+		Changes:   domain.DiffProjects(&versions[0].Project, &versions[1].Project),
+	}, nil
+}
+
+func buildProjectDiffUsecase() appcontext.Component {
+	return &ProjectDiff{
+		projectVersionRepository: domain.GetProjectVersionRepository(),
+	}
+}
+
+func init() {
+	appcontext.Current.Add(appcontext.ProjectDiffUsecase, buildProjectDiffUsecase, appcontext.ProjectVersionRepository)
+}
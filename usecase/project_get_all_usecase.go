@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 	"github.com/danilovalente/project-api/appcontext"
 	"github.com/danilovalente/project-api/config"
@@ -13,14 +14,17 @@ type ProjectGetAll struct {
 }
 
 //Execute with paging
-func (u *ProjectGetAll) Execute(lastProjectID string, pageSize int64) ([]*domain.Project, error) {
-	logger := config.GetLogger
-	defer logger().Sync()
+func (u *ProjectGetAll) Execute(ctx context.Context, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	ctx, execution := startExecution(ctx, "ProjectGetAll")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
 
-	projectList, err := u.projectRepository.GetAll(lastProjectID, pageSize)
+	projectList, err := u.projectRepository.GetAll(ctx, lastProjectID, pageSize)
 	if err != nil {
 		msg := fmt.Sprintf("Could not get the Project list. Message: %s\n", err.Error())
-		logger().Error(msg)
+		execution.fail(err)
+		logger.Error(msg)
 		return nil, err
 	}
 	return projectList, nil
@@ -33,8 +37,5 @@ func buildProjectGetAllUsecase() appcontext.Component {
 }
 
 func init() {
-	if config.Values.TestRun {
-		return
-	}
-	appcontext.Current.Add(appcontext.ProjectGetAllUsecase, buildProjectGetAllUsecase)
+	appcontext.Current.Add(appcontext.ProjectGetAllUsecase, buildProjectGetAllUsecase, appcontext.ProjectRepository)
 }
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//ProjectGetAsOf represents the Usecase which reconstructs a Project as it was at a date, from its versions
+type ProjectGetAsOf struct {
+	projectVersionRepository domain.ProjectVersionRepository
+}
+
+//Execute gets the Project with the provided ID as it was at the date. It is not found if it did not exist yet or was in the trash
+func (u *ProjectGetAsOf) Execute(ctx context.Context, ID string, asOf time.Time) (*domain.Project, error) {
+	ctx, execution := startExecution(ctx, "ProjectGetAsOf")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+
+	version, err := u.projectVersionRepository.GetAsOf(ctx, ID, asOf)
+	if err == nil && version.Project.Deleted() {
+		err = domain.NotFound(fmt.Sprintf("The Project with the ID: %s was deleted as of %s", ID, asOf.Format(time.RFC3339)))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Could not get the Project with ID: %s as of %s. Message: %s\n", ID, asOf.Format(time.RFC3339), err.Error())
+		execution.fail(err)
+		logger.Error(msg)
+		return nil, err
+	}
+	return &version.Project, nil
+}
+
+func buildProjectGetAsOfUsecase() appcontext.Component {
+	return &ProjectGetAsOf{
+		projectVersionRepository: domain.GetProjectVersionRepository(),
+	}
+}
+
+func init() {
+	appcontext.Current.Add(appcontext.ProjectGetAsOfUsecase, buildProjectGetAsOfUsecase, appcontext.ProjectVersionRepository)
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/danilovalente/project-api/appcontext"
@@ -14,15 +15,18 @@ type ProjectGetByID struct {
 }
 
 //Execute get the Project with the provided ID
-func (u *ProjectGetByID) Execute(ID string) (*domain.Project, error) {
-	logger := config.GetLogger
-	defer logger().Sync()
+func (u *ProjectGetByID) Execute(ctx context.Context, ID string) (*domain.Project, error) {
+	ctx, execution := startExecution(ctx, "ProjectGetByID")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
 
 	projectRepository := u.projectRepository
-	project, err := projectRepository.Get(ID)
+	project, err := projectRepository.Get(ctx, ID)
 	if err != nil {
 		msg := fmt.Sprintf("Could not get the Project. Message: %s\n", err.Error())
-		logger().Error(msg)
+		execution.fail(err)
+		logger.Error(msg)
 		return nil, err
 	}
 	return project, nil
@@ -36,8 +40,5 @@ func buildProjectGetByIDUsecase() appcontext.Component {
 }
 
 func init() {
-	if config.Values.TestRun {
-		return
-	}
-	appcontext.Current.Add(appcontext.ProjectGetByIDUsecase, buildProjectGetByIDUsecase)
+	appcontext.Current.Add(appcontext.ProjectGetByIDUsecase, buildProjectGetByIDUsecase, appcontext.ProjectRepository)
 }
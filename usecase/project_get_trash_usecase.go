@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//ProjectGetTrash represents the Usecase which lists the deleted Projects, which may still be restored
+type ProjectGetTrash struct {
+	projectRepository domain.ProjectRepository
+}
+
+//Execute with paging
+func (u *ProjectGetTrash) Execute(ctx context.Context, lastProjectID string, pageSize int64) ([]*domain.Project, error) {
+	ctx, execution := startExecution(ctx, "ProjectGetTrash")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+
+	projectList, err := u.projectRepository.GetTrash(ctx, lastProjectID, pageSize)
+	if err != nil {
+		msg := fmt.Sprintf("Could not get the deleted Project list. Message: %s\n", err.Error())
+		execution.fail(err)
+		logger.Error(msg)
+		return nil, err
+	}
+	return projectList, nil
+}
+
+func buildProjectGetTrashUsecase() appcontext.Component {
+	return &ProjectGetTrash{
+		projectRepository: domain.GetProjectRepository(),
+	}
+}
+
+func init() {
+	appcontext.Current.Add(appcontext.ProjectGetTrashUsecase, buildProjectGetTrashUsecase, appcontext.ProjectRepository)
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//ProjectGetVersion represents the Usecase which gets a numbered version of a Project, kept even after the Project is purged
+type ProjectGetVersion struct {
+	projectVersionRepository domain.ProjectVersionRepository
+}
+
+//Execute gets the version number of the Project with the provided ID
+func (u *ProjectGetVersion) Execute(ctx context.Context, ID string, number int64) (*domain.ProjectVersion, error) {
+	ctx, execution := startExecution(ctx, "ProjectGetVersion")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+
+	version, err := u.projectVersionRepository.Get(ctx, ID, number)
+	if err != nil {
+		msg := fmt.Sprintf("Could not get the version %d of the Project with ID: %s. Message: %s\n", number, ID, err.Error())
+		execution.fail(err)
+		logger.Error(msg)
+		return nil, err
+	}
+	return version, nil
+}
+
+func buildProjectGetVersionUsecase() appcontext.Component {
+	return &ProjectGetVersion{
+		projectVersionRepository: domain.GetProjectVersionRepository(),
+	}
+}
+
+func init() {
+	appcontext.Current.Add(appcontext.ProjectGetVersionUsecase, buildProjectGetVersionUsecase, appcontext.ProjectVersionRepository)
+}
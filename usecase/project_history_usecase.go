@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//ProjectHistory represents the Usecase which lists the AuditEntry of a Project, kept even after the Project is purged
+type ProjectHistory struct {
+	auditRepository domain.AuditRepository
+}
+
+//Execute lists the changes of the Project with the provided ID, with paging
+func (u *ProjectHistory) Execute(ctx context.Context, ID string, lastEntryID string, pageSize int64) ([]*domain.AuditEntry, error) {
+	ctx, execution := startExecution(ctx, "ProjectHistory")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+
+	if _, err := primitive.ObjectIDFromHex(ID); err != nil {
+		err = domain.FieldViolation("projectId", domain.ViolationFormat, "projectId", ID)
+		execution.fail(err)
+		return nil, err
+	}
+	query := domain.AuditQuery{Entity: domain.AuditEntityProject, EntityID: ID, LastEntryID: lastEntryID, PageSize: pageSize}
+	entries, err := u.auditRepository.Find(ctx, query)
+	if err != nil {
+		msg := fmt.Sprintf("Could not get the history of the Project with ID: %s. Message: %s\n", ID, err.Error())
+		execution.fail(err)
+		logger.Error(msg)
+		return nil, err
+	}
+	return entries, nil
+}
+
+func buildProjectHistoryUsecase() appcontext.Component {
+	return &ProjectHistory{
+		auditRepository: domain.GetAuditRepository(),
+	}
+}
+
+func init() {
+	appcontext.Current.Add(appcontext.ProjectHistoryUsecase, buildProjectHistoryUsecase, appcontext.AuditRepository)
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//ProjectPrice represents the Usecase which prices a quantity of a Project, following its pricing tiers and discount
+type ProjectPrice struct {
+	projectRepository domain.ProjectRepository
+	rounding          domain.RoundingMode
+}
+
+//Execute prices the decimal quantity, in the TimeUnit of the Project with the provided ID, itemized per pricing tier
+func (u *ProjectPrice) Execute(ctx context.Context, ID string, quantity string) (*domain.PriceBreakdown, error) {
+	ctx, execution := startExecution(ctx, "ProjectPrice")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+
+	exactQuantity, err := domain.ParseDecimal(quantity)
+	if err != nil {
+		err = domain.FieldViolation("quantity", domain.ViolationFormat, "quantity", quantity)
+		execution.fail(err)
+		logger.Error(err.Error())
+		return nil, err
+	}
+	project, err := u.projectRepository.Get(ctx, ID)
+	if err != nil {
+		execution.fail(err)
+		logger.Errorf("Could not get the Project. Message: %s", err.Error())
+		return nil, err
+	}
+	breakdown, err := project.Price(exactQuantity, u.rounding)
+	if err != nil {
+		execution.fail(err)
+		logger.Errorf("Could not price the Project. Message: %s", err.Error())
+		return nil, err
+	}
+	return breakdown, nil
+}
+
+func buildProjectPriceUsecase() (appcontext.Component, error) {
+	rounding, err := domain.ParseRoundingMode(config.Values.PricingRounding)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid PRICING_ROUNDING: %w", err)
+	}
+	return &ProjectPrice{
+		projectRepository: domain.GetProjectRepository(),
+		rounding:          rounding,
+	}, nil
+}
+
+func init() {
+	appcontext.Current.AddFactory(appcontext.ProjectPriceUsecase, buildProjectPriceUsecase, appcontext.ProjectRepository)
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//ProjectPurge represents the Usecase which permanently removes the Projects kept in the trash longer than the retention.
+//While started, it runs in background every interval. The instances may purge concurrently, as the purge is idempotent
+type ProjectPurge struct {
+	projectRepository domain.ProjectRepository
+	retention         time.Duration
+	interval          time.Duration
+	cancel            context.CancelFunc
+	done              chan struct{}
+	mutex             sync.Mutex
+}
+
+//Execute removes the Projects deleted before the retention, returning how many were removed. The purge is audited as made by the domain.SystemActor
+func (u *ProjectPurge) Execute(ctx context.Context) (int64, error) {
+	ctx = appcontext.WithVerifiedActor(ctx, domain.SystemActor)
+	ctx, execution := startExecution(ctx, "ProjectPurge")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+
+	deletedBefore := time.Now().Add(-u.retention)
+	purged, err := u.projectRepository.Purge(ctx, deletedBefore)
+	if err != nil {
+		execution.fail(err)
+		logger.Errorf("Could not purge the Projects deleted before %s. Message: %s", deletedBefore.Format(time.RFC3339), err.Error())
+		return 0, err
+	}
+	if purged > 0 {
+		logger.Infof("Purged %d Projects deleted before %s", purged, deletedBefore.Format(time.RFC3339))
+	}
+	return purged, nil
+}
+
+//Start purging the trash in background every interval, unless the interval is zero
+func (u *ProjectPurge) Start(ctx context.Context) error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if u.interval <= 0 {
+		return nil
+	}
+	purgeCtx, cancel := context.WithCancel(context.Background())
+	u.cancel = cancel
+	u.done = make(chan struct{})
+	go u.run(purgeCtx)
+	return nil
+}
+
+//Stop the background purge, waiting for the running one
+func (u *ProjectPurge) Stop(ctx context.Context) error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if u.cancel == nil {
+		return nil
+	}
+	u.cancel()
+	u.cancel = nil
+	select {
+	case <-u.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//run the purge every interval until the context is cancelled. The failures are logged by Execute and retried in the next run
+func (u *ProjectPurge) run(ctx context.Context) {
+	defer close(u.done)
+	ticker := time.NewTicker(u.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = u.Execute(ctx)
+		}
+	}
+}
+
+func buildProjectPurgeUsecase() (appcontext.Component, error) {
+	//A retention below one day would purge the Projects just deleted, or the whole trash
+	if config.Values.TrashRetentionDays < 1 {
+		return nil, fmt.Errorf("Invalid TRASH_RETENTION_DAYS: %d. The Projects must be kept in the trash for at least 1 day", config.Values.TrashRetentionDays)
+	}
+	return &ProjectPurge{
+		projectRepository: domain.GetProjectRepository(),
+		retention:         time.Duration(config.Values.TrashRetentionDays) * 24 * time.Hour,
+		interval:          config.Values.TrashPurgeInterval,
+	}, nil
+}
+
+func init() {
+	appcontext.Current.AddFactory(appcontext.ProjectPurgeUsecase, buildProjectPurgeUsecase, appcontext.ProjectRepository)
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//ProjectQuote represents the Usecase which prices a quantity of work in any time unit, converted to the TimeUnit of the Project
+type ProjectQuote struct {
+	projectRepository domain.ProjectRepository
+	calendar          *domain.Calendar
+	rounding          domain.RoundingMode
+}
+
+//Execute converts the quantity of the request to the TimeUnit of the Project with the provided ID and prices it
+func (u *ProjectQuote) Execute(ctx context.Context, ID string, request domain.QuoteRequest) (*domain.Quote, error) {
+	ctx, execution := startExecution(ctx, "ProjectQuote")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+
+	quantity, err := validQuoteRequest(&request)
+	if err != nil {
+		execution.fail(err)
+		logger.Error(err.Error())
+		return nil, err
+	}
+	project, err := u.projectRepository.Get(ctx, ID)
+	if err != nil {
+		execution.fail(err)
+		logger.Errorf("Could not get the Project. Message: %s", err.Error())
+		return nil, err
+	}
+	convertedQuantity, err := u.calendar.Convert(quantity, request.Unit, project.TimeUnit)
+	if err != nil {
+		execution.fail(err)
+		logger.Errorf("Could not convert the quantity to the time unit of the Project. Message: %s", err.Error())
+		return nil, err
+	}
+	breakdown, err := project.Price(convertedQuantity, u.rounding)
+	if err != nil {
+		execution.fail(err)
+		logger.Errorf("Could not price the Project. Message: %s", err.Error())
+		return nil, err
+	}
+	return &domain.Quote{
+		Quantity:          string(request.Quantity),
+		Unit:              request.Unit,
+		ConvertedQuantity: breakdown.Quantity,
+		TimeUnit:          project.TimeUnit,
+		UnitPrice:         domain.Money{Amount: project.UnitPrice.Amount, Currency: project.UnitPrice.Currency},
+		Total:             breakdown.Total,
+		Breakdown:         *breakdown,
+	}, nil
+}
+
+//validQuoteRequest returns the exact quantity of the request, if it is valid
+func validQuoteRequest(request *domain.QuoteRequest) (*big.Rat, error) {
+	violations := domain.Violations{}
+	request.Quantity = domain.QuoteQuantity(strings.TrimSpace(string(request.Quantity)))
+	quantity, err := domain.ParseDecimal(string(request.Quantity))
+	switch {
+	case request.Quantity == "":
+		violations.Add("quantity", domain.ViolationRequired, "quantity")
+	case err != nil:
+		violations.Add("quantity", domain.ViolationFormat, "quantity", request.Quantity)
+	case quantity.Sign() <= 0:
+		violations.Add("quantity", domain.ViolationPositive, "quantity")
+	}
+	if !domain.ValidTimeUnit(request.Unit) {
+		violations.Add("unit", domain.ViolationOneOf, "unit", strings.Join(domain.TimeUnits, ", "))
+	}
+	if err := violations.Err("quote.invalid"); err != nil {
+		return nil, err
+	}
+	return quantity, nil
+}
+
+func buildProjectQuoteUsecase() (appcontext.Component, error) {
+	rounding, err := domain.ParseRoundingMode(config.Values.PricingRounding)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid PRICING_ROUNDING: %w", err)
+	}
+	calendar, err := domain.NewCalendar(config.Values.CalendarHoursPerDay, config.Values.CalendarDaysPerWeek, config.Values.CalendarWeeksPerMonth)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid CALENDAR settings: %w", err)
+	}
+	return &ProjectQuote{
+		projectRepository: domain.GetProjectRepository(),
+		calendar:          calendar,
+		rounding:          rounding,
+	}, nil
+}
+
+func init() {
+	appcontext.Current.AddFactory(appcontext.ProjectQuoteUsecase, buildProjectQuoteUsecase, appcontext.ProjectRepository)
+}
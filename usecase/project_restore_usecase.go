@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//ProjectRestore represents the Usecase which takes a deleted Project back from the trash
+type ProjectRestore struct {
+	projectRepository domain.ProjectRepository
+}
+
+//Execute restores the deleted Project with the provided ID
+func (u *ProjectRestore) Execute(ctx context.Context, ID string) (*domain.Project, error) {
+	ctx, execution := startExecution(ctx, "ProjectRestore")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+
+	project, err := u.projectRepository.Restore(ctx, ID)
+	if err != nil {
+		msg := fmt.Sprintf("Could not restore the Project with ID: %s. Message: %s\n", ID, err.Error())
+		execution.fail(err)
+		logger.Error(msg)
+		return nil, err
+	}
+	config.GetMetrics().ProjectChanged("restored", project.UnitPrice.Currency, project.TimeUnit)
+	publish(ctx, domain.ProjectRestoredEvent, ID)
+	return project, nil
+}
+
+func buildProjectRestoreUsecase() appcontext.Component {
+	return &ProjectRestore{
+		projectRepository: domain.GetProjectRepository(),
+	}
+}
+
+func init() {
+	appcontext.Current.Add(appcontext.ProjectRestoreUsecase, buildProjectRestoreUsecase, appcontext.ProjectRepository)
+}
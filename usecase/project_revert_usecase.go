@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//ProjectRevert represents the Usecase which updates a Project back to the fields of one of its versions, creating a new version
+type ProjectRevert struct {
+	projectRepository        domain.ProjectRepository
+	projectVersionRepository domain.ProjectVersionRepository
+}
+
+//Execute reverts the Project with the provided ID to its version number. A deleted Project must be restored first
+func (u *ProjectRevert) Execute(ctx context.Context, ID string, number int64) (*domain.Project, error) {
+	ctx, execution := startExecution(ctx, "ProjectRevert")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+
+	project, err := u.revert(ctx, ID, number)
+	if err != nil {
+		msg := fmt.Sprintf("Could not revert the Project with ID: %s to the version %d. Message: %s\n", ID, number, err.Error())
+		execution.fail(err)
+		logger.Error(msg)
+		return nil, err
+	}
+	config.GetMetrics().ProjectChanged("reverted", project.UnitPrice.Currency, project.TimeUnit)
+	publish(ctx, domain.ProjectUpdatedEvent, ID)
+	return project, nil
+}
+
+func (u *ProjectRevert) revert(ctx context.Context, ID string, number int64) (*domain.Project, error) {
+	version, err := u.projectVersionRepository.Get(ctx, ID, number)
+	if err != nil {
+		return nil, err
+	}
+	project, err := u.projectRepository.Get(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	project.RevertTo(version)
+	//The version may have been valid under rules which changed since then
+	if valid, err := project.Valid(); !valid {
+		return nil, err
+	}
+	return u.projectRepository.Update(ctx, project)
+}
+
+func buildProjectRevertUsecase() appcontext.Component {
+	return &ProjectRevert{
+		projectRepository:        domain.GetProjectRepository(),
+		projectVersionRepository: domain.GetProjectVersionRepository(),
+	}
+}
+
+func init() {
+	appcontext.Current.Add(appcontext.ProjectRevertUsecase, buildProjectRevertUsecase, appcontext.ProjectRepository, appcontext.ProjectVersionRepository)
+}
@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/danilovalente/project-api/appcontext"
 	"github.com/danilovalente/project-api/config"
 	"github.com/danilovalente/project-api/domain"
@@ -12,21 +14,27 @@ type ProjectUpdate struct {
 }
 
 //Execute updates the project
-func (u *ProjectUpdate) Execute(project *domain.Project) error {
-	logger := config.GetLogger
-	defer logger().Sync()
-	logger().Debugf("Project %+v \n", project)
+func (u *ProjectUpdate) Execute(ctx context.Context, project *domain.Project) error {
+	ctx, execution := startExecution(ctx, "ProjectUpdate")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+	logger.Debugf("Project %+v \n", project)
 
 	valid, err := project.Valid()
 	if !valid {
-		logger().Error(err.Error())
+		execution.fail(err)
+		logger.Error(err.Error())
 		return err
 	}
-	_, err = u.projectRepository.Update(project)
+	_, err = u.projectRepository.Update(ctx, project)
 	if err != nil {
-		logger().Errorf("Could not update project into repository. Error %s", err.Error())
+		execution.fail(err)
+		logger.Errorf("Could not update project into repository. Error %s", err.Error())
 		return err
 	}
+	config.GetMetrics().ProjectChanged("updated", project.UnitPrice.Currency, project.TimeUnit)
+	publish(ctx, domain.ProjectUpdatedEvent, project.ID.Hex())
 	return nil
 }
 
@@ -37,8 +45,5 @@ func buildProjectUpdateUsecase() appcontext.Component {
 }
 
 func init() {
-	if config.Values.TestRun {
-		return
-	}
-	appcontext.Current.Add(appcontext.ProjectUpdateUsecase, buildProjectUpdateUsecase)
+	appcontext.Current.Add(appcontext.ProjectUpdateUsecase, buildProjectUpdateUsecase, appcontext.ProjectRepository)
 }
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+)
+
+//TaxCalculate represents the Usecase which calculates the taxes of a price with the TaxRates effective in the jurisdiction
+type TaxCalculate struct {
+	taxRateRepository domain.TaxRateRepository
+	rounding          domain.RoundingMode
+}
+
+//Execute splits the price of the query in its net amount, the tax lines and the gross amount
+func (u *TaxCalculate) Execute(ctx context.Context, query domain.TaxQuery) (*domain.TaxBreakdown, error) {
+	ctx, execution := startExecution(ctx, "TaxCalculate")
+	defer execution.end()
+	logger := config.GetContextLogger(ctx)
+	defer logger.Sync()
+
+	if err := validTaxQuery(&query); err != nil {
+		execution.fail(err)
+		logger.Error(err.Error())
+		return nil, err
+	}
+	if query.Date.IsZero() {
+		query.Date = time.Now()
+	}
+	rates, err := u.taxRateRepository.Find(ctx, query.Jurisdiction, query.Category, query.Date)
+	if err != nil {
+		execution.fail(err)
+		logger.Errorf("Could not find the tax rates. Message: %s", err.Error())
+		return nil, err
+	}
+	if len(rates) == 0 {
+		err = domain.NotFound(fmt.Sprintf("Could not find the tax rates of the category %s in %s at %s", query.Category, query.Jurisdiction, query.Date.Format(time.RFC3339)))
+		execution.fail(err)
+		logger.Error(err.Error())
+		return nil, err
+	}
+	breakdown, err := domain.CalculateTaxes(query.Price, rates, query.TaxInclusive, u.rounding)
+	if err != nil {
+		execution.fail(err)
+		logger.Errorf("Could not calculate the taxes. Message: %s", err.Error())
+		return nil, err
+	}
+	return breakdown, nil
+}
+
+func validTaxQuery(query *domain.TaxQuery) error {
+	violations := domain.Violations{}
+	if query.Price.Validate("price", &violations) && !query.Price.IsPositive() {
+		violations.Add("price.amount", domain.ViolationPositive, "price.amount")
+	}
+	if strings.TrimSpace(query.Jurisdiction) == "" {
+		violations.Add("jurisdiction", domain.ViolationRequired, "jurisdiction")
+	}
+	if strings.TrimSpace(query.Category) == "" {
+		violations.Add("category", domain.ViolationRequired, "category")
+	}
+	return violations.Err("taxQuery.invalid")
+}
+
+func buildTaxCalculateUsecase() (appcontext.Component, error) {
+	rounding, err := domain.ParseRoundingMode(config.Values.TaxRounding)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid TAX_ROUNDING: %w", err)
+	}
+	return &TaxCalculate{
+		taxRateRepository: domain.GetTaxRateRepository(),
+		rounding:          rounding,
+	}, nil
+}
+
+func init() {
+	appcontext.Current.AddFactory(appcontext.TaxCalculateUsecase, buildTaxCalculateUsecase, appcontext.TaxRateRepository)
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilovalente/project-api/appcontext"
+	"github.com/danilovalente/project-api/config"
+	"github.com/danilovalente/project-api/domain"
+	_ "github.com/danilovalente/project-api/gateway/customlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	config.UseProfile(appcontext.ProfileTest)
+}
+
+//fakeTaxRateRepository returns its rates for any jurisdiction, category and date, recording the last query
+type fakeTaxRateRepository struct {
+	rates        []domain.TaxRate
+	jurisdiction string
+	category     string
+	date         time.Time
+}
+
+func (repo *fakeTaxRateRepository) Find(ctx context.Context, jurisdiction string, category string, date time.Time) ([]domain.TaxRate, error) {
+	repo.jurisdiction, repo.category, repo.date = jurisdiction, category, date
+	return repo.rates, nil
+}
+
+func errorCode(err error) int {
+	if identifiableError, ok := err.(domain.IdentifiableError); ok {
+		return identifiableError.GetCode()
+	}
+	return 0
+}
+
+func TestTaxCalculate(t *testing.T) {
+	effectiveFrom := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeTaxRateRepository{rates: []domain.TaxRate{
+		{Jurisdiction: "CA", Category: "consulting", Name: "GST", Rate: "5", EffectiveFrom: effectiveFrom},
+		{Jurisdiction: "CA-QC", Category: "consulting", Name: "QST", Rate: "9.975", EffectiveFrom: effectiveFrom},
+	}}
+	usecase := &TaxCalculate{taxRateRepository: repo, rounding: domain.RoundHalfEven}
+	date := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		price         int64
+		taxInclusive  bool
+		expectedNet   int64
+		expectedGross int64
+	}{
+		{name: "Exclusive", price: 10000, expectedNet: 10000, expectedGross: 11498},
+		{name: "Inclusive", price: 11498, taxInclusive: true, expectedNet: 10000, expectedGross: 11498},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := domain.TaxQuery{Price: domain.Money{Amount: tt.price, Currency: "CAD"}, Jurisdiction: "CA-QC", Category: "consulting", Date: date, TaxInclusive: tt.taxInclusive}
+			breakdown, err := usecase.Execute(context.Background(), query)
+			if assert.NoError(t, err) {
+				assert.Equal(t, domain.Money{Amount: tt.expectedNet, Currency: "CAD"}, breakdown.Net)
+				assert.Equal(t, domain.Money{Amount: tt.expectedGross, Currency: "CAD"}, breakdown.Gross)
+				if assert.Len(t, breakdown.Taxes, 2) {
+					assert.Equal(t, domain.TaxLine{Name: "GST", Jurisdiction: "CA", Rate: "5", Amount: domain.Money{Amount: 501, Currency: "CAD"}}, breakdown.Taxes[0])
+					assert.Equal(t, domain.TaxLine{Name: "QST", Jurisdiction: "CA-QC", Rate: "9.975", Amount: domain.Money{Amount: 997, Currency: "CAD"}}, breakdown.Taxes[1])
+				}
+			}
+			assert.Equal(t, "CA-QC", repo.jurisdiction)
+			assert.Equal(t, "consulting", repo.category)
+			assert.Equal(t, date, repo.date)
+		})
+	}
+}
+
+func TestTaxCalculateErrors(t *testing.T) {
+	usecase := &TaxCalculate{taxRateRepository: &fakeTaxRateRepository{}, rounding: domain.RoundHalfEven}
+	ctx := context.Background()
+
+	_, err := usecase.Execute(ctx, domain.TaxQuery{Price: domain.Money{Amount: 0, Currency: "EUR"}})
+	if violations, ok := err.(domain.ConstraintViolationError); assert.True(t, ok) {
+		assert.Len(t, violations.Violations, 3)
+	}
+
+	//Without rates in the jurisdiction
+	_, err = usecase.Execute(ctx, domain.TaxQuery{Price: domain.Money{Amount: 10000, Currency: "EUR"}, Jurisdiction: "PT", Category: "consulting"})
+	assert.Equal(t, 404, errorCode(err))
+}